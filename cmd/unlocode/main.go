@@ -0,0 +1,180 @@
+// Command unlocode imports ports from local files of UN/LOCODE code list into ports service, i.e.
+//
+//	unlocode -subdivisions "2023-1 SubdivisionCodes.csv" "2023-1 UNLOCODE CodeListPart1.csv" \
+//		"2023-1 UNLOCODE CodeListPart2.csv" "2023-1 UNLOCODE CodeListPart3.csv"
+//
+// It prints summary of the import as json, the same as POST /ports of webapp.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/caarlos0/env/v6"
+	"go.uber.org/zap"
+
+	"github.com/arturskrzydlo/ports/internal/common/grpc"
+
+	"github.com/arturskrzydlo/ports/internal/common/pb"
+
+	"github.com/arturskrzydlo/ports/internal/unlocode"
+)
+
+// errorPolicies map values of error-policy flag, which are the same as error_policy query param of webapp
+var errorPolicies = map[string]pb.ErrorPolicy{
+	"skip_invalid":   pb.ErrorPolicy_ERROR_POLICY_SKIP_INVALID,
+	"fail_fast":      pb.ErrorPolicy_ERROR_POLICY_FAIL_FAST,
+	"all_or_nothing": pb.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING,
+}
+
+type appConfig struct {
+	PortsGRPServerAddress  string `env:"PORTS_GRPC_ADDRESS" envDefault:"0.0.0.0:8090"`
+	GRPCKeepAliveInSeconds int    `env:"GRPC_KEEP_ALIVE_IN_SECONDS" envDefault:"60"`
+}
+
+func main() {
+	subdivisionsPath := flag.String("subdivisions", "",
+		"file of UN/LOCODE subdivision codes, provinces are ISO 3166-2 codes without it")
+	errorPolicy := flag.String("error-policy", "skip_invalid",
+		"what happens when ports are rejected, either skip_invalid, fail_fast or all_or_nothing")
+	mode := flag.String("mode", "upsert", "either upsert or replace, which deletes stored ports missing in the files")
+	atomic := flag.Bool("atomic", false, "store ports in a single transaction")
+	dryRun := flag.Bool("dry-run", false, "only report which ports would be created, updated and deleted")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] CODE_LIST_FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var cfg appConfig
+	if err := env.Parse(&cfg); err != nil {
+		panic("failed to parse app config: " + err.Error())
+	}
+
+	log, err := zap.NewProduction()
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	opts, err := parseOptions(*errorPolicy, *mode)
+	if err != nil {
+		fmt.Fprintln(flag.CommandLine.Output(), err)
+		os.Exit(2)
+	}
+	opts.Atomic = *atomic
+	opts.DryRun = *dryRun
+	summary, err := importFiles(cfg, opts, *subdivisionsPath, flag.Args())
+	if err != nil {
+		log.Error("failed to import UN/LOCODE code list", zap.Error(err))
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(summaryFromPB(summary)); err != nil {
+		log.Error("failed to write summary of the import", zap.Error(err))
+		os.Exit(1)
+	}
+	if summary.Aborted {
+		os.Exit(1)
+	}
+}
+
+func parseOptions(errorPolicy, mode string) (*pb.IngestOptions, error) {
+	policy, ok := errorPolicies[errorPolicy]
+	if !ok {
+		return nil, fmt.Errorf("error-policy must be one of skip_invalid, fail_fast or all_or_nothing, got %q",
+			errorPolicy)
+	}
+	if mode != "upsert" && mode != "replace" {
+		return nil, fmt.Errorf("mode must be either upsert or replace, got %q", mode)
+	}
+	return &pb.IngestOptions{ErrorPolicy: policy, Replace: mode == "replace"}, nil
+}
+
+// importFiles sends ports of all the files to ports service as a single ingestion, so they can replace
+// stored ports together.
+func importFiles(cfg appConfig, opts *pb.IngestOptions, subdivisionsPath string, paths []string,
+) (*pb.IngestSummary, error) {
+	var subdivisions map[string]string
+	if subdivisionsPath != "" {
+		file, err := os.Open(subdivisionsPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if subdivisions, err = unlocode.ReadSubdivisions(file); err != nil {
+			return nil, fmt.Errorf("%s: %w", subdivisionsPath, err)
+		}
+	}
+
+	files := make([]*os.File, 0, len(paths))
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	conn, err := grpc.NewClientConnectionContext(ctx, cfg.PortsGRPServerAddress, cfg.GRPCKeepAliveInSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a gRPC connection to ports service: %w", err)
+	}
+	defer conn.Close()
+
+	return unlocode.Import(ctx, pb.NewPortServiceClient(conn), opts, files, subdivisions)
+}
+
+// summary is the summary of the import printed the same way as the one of POST /ports of webapp.
+type summary struct {
+	Created    uint32      `json:"created"`
+	Updated    uint32      `json:"updated"`
+	Rejected   uint32      `json:"rejected"`
+	Errors     []portError `json:"errors"`
+	CreatedIDs []string    `json:"created_ids"`
+	UpdatedIDs []string    `json:"updated_ids"`
+	Aborted    bool        `json:"aborted"`
+	Deleted    uint32      `json:"deleted"`
+	DeletedIDs []string    `json:"deleted_ids"`
+	DryRun     bool        `json:"dry_run"`
+}
+
+// portError is a rejected port, Offset is counted from the beginning of the first file.
+type portError struct {
+	PortID string `json:"port_id"`
+	Reason string `json:"reason"`
+	Offset int64  `json:"offset"`
+}
+
+func summaryFromPB(summaryPb *pb.IngestSummary) *summary {
+	portErrors := make([]portError, len(summaryPb.Errors))
+	for i, portErr := range summaryPb.Errors {
+		portErrors[i] = portError{PortID: portErr.PortId, Reason: portErr.Reason, Offset: portErr.Offset}
+	}
+	return &summary{
+		Created:    summaryPb.Created,
+		Updated:    summaryPb.Updated,
+		Rejected:   summaryPb.Rejected,
+		Errors:     portErrors,
+		CreatedIDs: summaryPb.CreatedIds,
+		UpdatedIDs: summaryPb.UpdatedIds,
+		Aborted:    summaryPb.Aborted,
+		Deleted:    summaryPb.Deleted,
+		DeletedIDs: summaryPb.DeletedIds,
+		DryRun:     summaryPb.DryRun,
+	}
+}
@@ -26,6 +26,10 @@ type appConfig struct {
 	ReadHeaderTimeout int    `env:"READ_HEADER_TIMEOUT_IN_SEC" envDefault:"5"`
 	WriteTimeout      int    `env:"WRITE_TIMEOUT_IN_SEC" envDefault:"5"`
 	IdleTimeout       int    `env:"IDLE_TIMEOUT_IN_SEC" envDefault:"5"`
+	// UploadTimeout replaces ReadTimeout and WriteTimeout of requests uploading ports, 0 means no limit
+	UploadTimeout int `env:"UPLOAD_TIMEOUT_IN_SEC" envDefault:"600"`
+	// MaxImportSize limits size of ports imported in background, 0 means no limit
+	MaxImportSize int64 `env:"MAX_IMPORT_SIZE_IN_MB" envDefault:"1024"`
 
 	PortsGRPServerAddress  string `env:"PORTS_GRPC_ADDRESS" envDefault:"0.0.0.0:8090"`
 	GRPCKeepAliveInSeconds int    `eng:"GRPC_KEEP_ALIVE_IN_SECONDS" envDefault:"60"`
@@ -77,23 +81,24 @@ func main() {
 		IdleTimeout:       time.Duration(cfg.IdleTimeout) * time.Second,
 	}
 
-	go cancelOnSignal(cancel, signalCh, log, srv)
-
-	handler := webapp.NewServiceHandler(service, srv, log)
+	handler := webapp.NewServiceHandler(service, srv, time.Duration(cfg.UploadTimeout)*time.Second,
+		cfg.MaxImportSize<<20, log)
 	handler.Register(mux)
 
+	go cancelOnSignal(cancel, signalCh, log, handler)
+
 	log.Info("Successfully started webapp service")
 	handler.Run()
 }
 
-func cancelOnSignal(cancel context.CancelFunc, ch chan os.Signal, log *zap.Logger, server *http.Server) {
+func cancelOnSignal(cancel context.CancelFunc, ch chan os.Signal, log *zap.Logger, handler *webapp.ServiceHandler) {
 	sig := <-ch
 	log.Info("Shutting down application on signal", zap.String("signal", sig.String()))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := server.Shutdown(ctx)
+	err := handler.Shutdown(ctx)
 	if err != nil {
 		log.Error("failed to shutdown a server", zap.Error(err))
 	}
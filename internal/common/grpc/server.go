@@ -43,7 +43,7 @@ func NewServer(address string, logger *zap.Logger, options ...grpc.ServerOption)
 }
 
 // Run runs the gRPC server until the provided context is cancelled.
-// When that happens it shuts down the service.
+// When that happens it shuts down the service and returns once it's stopped.
 func (s *Server) Run(ctx context.Context) error {
 	lis, err := net.Listen("tcp", s.address)
 	if err != nil {
@@ -51,11 +51,17 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 	defer lis.Close()
 
-	go s.handleShutdown(ctx)
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		s.handleShutdown(ctx)
+	}()
 
 	if err := s.grpc.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 		return fmt.Errorf("gRPC server failed to start serving: %w", err)
 	}
+	// Serve returns as soon as the server stops listening, wait until in-flight requests are finished
+	<-shutdownDone
 	return nil
 }
 
@@ -25,7 +25,20 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PortServiceClient interface {
 	CreatePort(ctx context.Context, in *CreatePortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	GetPorts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetPortsResponse, error)
+	UpdatePort(ctx context.Context, in *UpdatePortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeletePort(ctx context.Context, in *DeletePortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetPort(ctx context.Context, in *GetPortRequest, opts ...grpc.CallOption) (*GetPortResponse, error)
+	GetPorts(ctx context.Context, in *GetPortsRequest, opts ...grpc.CallOption) (*GetPortsResponse, error)
+	ListPorts(ctx context.Context, in *ListPortsRequest, opts ...grpc.CallOption) (*ListPortsResponse, error)
+	SearchPorts(ctx context.Context, in *SearchPortsRequest, opts ...grpc.CallOption) (*SearchPortsResponse, error)
+	FindNearestPorts(ctx context.Context, in *FindNearestPortsRequest, opts ...grpc.CallOption) (*FindNearestPortsResponse, error)
+	FindPortsInBoundingBox(ctx context.Context, in *FindPortsInBoundingBoxRequest, opts ...grpc.CallOption) (*FindPortsInBoundingBoxResponse, error)
+	StreamPorts(ctx context.Context, in *StreamPortsRequest, opts ...grpc.CallOption) (PortService_StreamPortsClient, error)
+	// StreamCreatePorts reports every rejected port as soon as it's rejected, the summary is the last message
+	// of the response stream
+	StreamCreatePorts(ctx context.Context, opts ...grpc.CallOption) (PortService_StreamCreatePortsClient, error)
+	// DiffPorts compares every port of the stream to the stored one without changing anything
+	DiffPorts(ctx context.Context, opts ...grpc.CallOption) (PortService_DiffPortsClient, error)
 }
 
 type portServiceClient struct {
@@ -45,7 +58,34 @@ func (c *portServiceClient) CreatePort(ctx context.Context, in *CreatePortReques
 	return out, nil
 }
 
-func (c *portServiceClient) GetPorts(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetPortsResponse, error) {
+func (c *portServiceClient) UpdatePort(ctx context.Context, in *UpdatePortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/ports.PortService/UpdatePort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) DeletePort(ctx context.Context, in *DeletePortRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/ports.PortService/DeletePort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) GetPort(ctx context.Context, in *GetPortRequest, opts ...grpc.CallOption) (*GetPortResponse, error) {
+	out := new(GetPortResponse)
+	err := c.cc.Invoke(ctx, "/ports.PortService/GetPort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) GetPorts(ctx context.Context, in *GetPortsRequest, opts ...grpc.CallOption) (*GetPortsResponse, error) {
 	out := new(GetPortsResponse)
 	err := c.cc.Invoke(ctx, "/ports.PortService/GetPorts", in, out, opts...)
 	if err != nil {
@@ -54,12 +94,158 @@ func (c *portServiceClient) GetPorts(ctx context.Context, in *emptypb.Empty, opt
 	return out, nil
 }
 
+func (c *portServiceClient) ListPorts(ctx context.Context, in *ListPortsRequest, opts ...grpc.CallOption) (*ListPortsResponse, error) {
+	out := new(ListPortsResponse)
+	err := c.cc.Invoke(ctx, "/ports.PortService/ListPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) SearchPorts(ctx context.Context, in *SearchPortsRequest, opts ...grpc.CallOption) (*SearchPortsResponse, error) {
+	out := new(SearchPortsResponse)
+	err := c.cc.Invoke(ctx, "/ports.PortService/SearchPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) FindNearestPorts(ctx context.Context, in *FindNearestPortsRequest, opts ...grpc.CallOption) (*FindNearestPortsResponse, error) {
+	out := new(FindNearestPortsResponse)
+	err := c.cc.Invoke(ctx, "/ports.PortService/FindNearestPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) FindPortsInBoundingBox(ctx context.Context, in *FindPortsInBoundingBoxRequest, opts ...grpc.CallOption) (*FindPortsInBoundingBoxResponse, error) {
+	out := new(FindPortsInBoundingBoxResponse)
+	err := c.cc.Invoke(ctx, "/ports.PortService/FindPortsInBoundingBox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portServiceClient) StreamPorts(ctx context.Context, in *StreamPortsRequest, opts ...grpc.CallOption) (PortService_StreamPortsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortService_ServiceDesc.Streams[0], "/ports.PortService/StreamPorts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portServiceStreamPortsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PortService_StreamPortsClient interface {
+	Recv() (*Port, error)
+	grpc.ClientStream
+}
+
+type portServiceStreamPortsClient struct {
+	grpc.ClientStream
+}
+
+func (x *portServiceStreamPortsClient) Recv() (*Port, error) {
+	m := new(Port)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portServiceClient) StreamCreatePorts(ctx context.Context, opts ...grpc.CallOption) (PortService_StreamCreatePortsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortService_ServiceDesc.Streams[1], "/ports.PortService/StreamCreatePorts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portServiceStreamCreatePortsClient{stream}
+	return x, nil
+}
+
+type PortService_StreamCreatePortsClient interface {
+	Send(*StreamCreatePortsRequest) error
+	Recv() (*StreamCreatePortsResponse, error)
+	grpc.ClientStream
+}
+
+type portServiceStreamCreatePortsClient struct {
+	grpc.ClientStream
+}
+
+func (x *portServiceStreamCreatePortsClient) Send(m *StreamCreatePortsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *portServiceStreamCreatePortsClient) Recv() (*StreamCreatePortsResponse, error) {
+	m := new(StreamCreatePortsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portServiceClient) DiffPorts(ctx context.Context, opts ...grpc.CallOption) (PortService_DiffPortsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortService_ServiceDesc.Streams[2], "/ports.PortService/DiffPorts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portServiceDiffPortsClient{stream}
+	return x, nil
+}
+
+type PortService_DiffPortsClient interface {
+	Send(*PortEntry) error
+	CloseAndRecv() (*PortsDiff, error)
+	grpc.ClientStream
+}
+
+type portServiceDiffPortsClient struct {
+	grpc.ClientStream
+}
+
+func (x *portServiceDiffPortsClient) Send(m *PortEntry) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *portServiceDiffPortsClient) CloseAndRecv() (*PortsDiff, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PortsDiff)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PortServiceServer is the server API for PortService service.
 // All implementations must embed UnimplementedPortServiceServer
 // for forward compatibility
 type PortServiceServer interface {
 	CreatePort(context.Context, *CreatePortRequest) (*emptypb.Empty, error)
-	GetPorts(context.Context, *emptypb.Empty) (*GetPortsResponse, error)
+	UpdatePort(context.Context, *UpdatePortRequest) (*emptypb.Empty, error)
+	DeletePort(context.Context, *DeletePortRequest) (*emptypb.Empty, error)
+	GetPort(context.Context, *GetPortRequest) (*GetPortResponse, error)
+	GetPorts(context.Context, *GetPortsRequest) (*GetPortsResponse, error)
+	ListPorts(context.Context, *ListPortsRequest) (*ListPortsResponse, error)
+	SearchPorts(context.Context, *SearchPortsRequest) (*SearchPortsResponse, error)
+	FindNearestPorts(context.Context, *FindNearestPortsRequest) (*FindNearestPortsResponse, error)
+	FindPortsInBoundingBox(context.Context, *FindPortsInBoundingBoxRequest) (*FindPortsInBoundingBoxResponse, error)
+	StreamPorts(*StreamPortsRequest, PortService_StreamPortsServer) error
+	// StreamCreatePorts reports every rejected port as soon as it's rejected, the summary is the last message
+	// of the response stream
+	StreamCreatePorts(PortService_StreamCreatePortsServer) error
+	// DiffPorts compares every port of the stream to the stored one without changing anything
+	DiffPorts(PortService_DiffPortsServer) error
 	mustEmbedUnimplementedPortServiceServer()
 }
 
@@ -70,9 +256,49 @@ func (UnimplementedPortServiceServer) CreatePort(context.Context, *CreatePortReq
 	return nil, status.Errorf(codes.Unimplemented, "method CreatePort not implemented")
 }
 
-func (UnimplementedPortServiceServer) GetPorts(context.Context, *emptypb.Empty) (*GetPortsResponse, error) {
+func (UnimplementedPortServiceServer) UpdatePort(context.Context, *UpdatePortRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePort not implemented")
+}
+
+func (UnimplementedPortServiceServer) DeletePort(context.Context, *DeletePortRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePort not implemented")
+}
+
+func (UnimplementedPortServiceServer) GetPort(context.Context, *GetPortRequest) (*GetPortResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPort not implemented")
+}
+
+func (UnimplementedPortServiceServer) GetPorts(context.Context, *GetPortsRequest) (*GetPortsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPorts not implemented")
 }
+
+func (UnimplementedPortServiceServer) ListPorts(context.Context, *ListPortsRequest) (*ListPortsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPorts not implemented")
+}
+
+func (UnimplementedPortServiceServer) SearchPorts(context.Context, *SearchPortsRequest) (*SearchPortsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchPorts not implemented")
+}
+
+func (UnimplementedPortServiceServer) FindNearestPorts(context.Context, *FindNearestPortsRequest) (*FindNearestPortsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindNearestPorts not implemented")
+}
+
+func (UnimplementedPortServiceServer) FindPortsInBoundingBox(context.Context, *FindPortsInBoundingBoxRequest) (*FindPortsInBoundingBoxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindPortsInBoundingBox not implemented")
+}
+
+func (UnimplementedPortServiceServer) StreamPorts(*StreamPortsRequest, PortService_StreamPortsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPorts not implemented")
+}
+
+func (UnimplementedPortServiceServer) StreamCreatePorts(PortService_StreamCreatePortsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCreatePorts not implemented")
+}
+
+func (UnimplementedPortServiceServer) DiffPorts(PortService_DiffPortsServer) error {
+	return status.Errorf(codes.Unimplemented, "method DiffPorts not implemented")
+}
 func (UnimplementedPortServiceServer) mustEmbedUnimplementedPortServiceServer() {}
 
 // UnsafePortServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -104,8 +330,62 @@ func _PortService_CreatePort_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortService_UpdatePort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).UpdatePort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/UpdatePort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).UpdatePort(ctx, req.(*UpdatePortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_DeletePort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).DeletePort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/DeletePort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).DeletePort(ctx, req.(*DeletePortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_GetPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).GetPort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/GetPort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).GetPort(ctx, req.(*GetPortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PortService_GetPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(GetPortsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -117,11 +397,156 @@ func _PortService_GetPorts_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: "/ports.PortService/GetPorts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PortServiceServer).GetPorts(ctx, req.(*emptypb.Empty))
+		return srv.(PortServiceServer).GetPorts(ctx, req.(*GetPortsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortService_ListPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).ListPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/ListPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).ListPorts(ctx, req.(*ListPortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_SearchPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).SearchPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/SearchPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).SearchPorts(ctx, req.(*SearchPortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_FindNearestPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindNearestPortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).FindNearestPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/FindNearestPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).FindNearestPorts(ctx, req.(*FindNearestPortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_FindPortsInBoundingBox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindPortsInBoundingBoxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortServiceServer).FindPortsInBoundingBox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ports.PortService/FindPortsInBoundingBox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortServiceServer).FindPortsInBoundingBox(ctx, req.(*FindPortsInBoundingBoxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortService_StreamPorts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPortsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortServiceServer).StreamPorts(m, &portServiceStreamPortsServer{stream})
+}
+
+type PortService_StreamPortsServer interface {
+	Send(*Port) error
+	grpc.ServerStream
+}
+
+type portServiceStreamPortsServer struct {
+	grpc.ServerStream
+}
+
+func (x *portServiceStreamPortsServer) Send(m *Port) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PortService_StreamCreatePorts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PortServiceServer).StreamCreatePorts(&portServiceStreamCreatePortsServer{stream})
+}
+
+type PortService_StreamCreatePortsServer interface {
+	Send(*StreamCreatePortsResponse) error
+	Recv() (*StreamCreatePortsRequest, error)
+	grpc.ServerStream
+}
+
+type portServiceStreamCreatePortsServer struct {
+	grpc.ServerStream
+}
+
+func (x *portServiceStreamCreatePortsServer) Send(m *StreamCreatePortsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *portServiceStreamCreatePortsServer) Recv() (*StreamCreatePortsRequest, error) {
+	m := new(StreamCreatePortsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _PortService_DiffPorts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PortServiceServer).DiffPorts(&portServiceDiffPortsServer{stream})
+}
+
+type PortService_DiffPortsServer interface {
+	SendAndClose(*PortsDiff) error
+	Recv() (*PortEntry, error)
+	grpc.ServerStream
+}
+
+type portServiceDiffPortsServer struct {
+	grpc.ServerStream
+}
+
+func (x *portServiceDiffPortsServer) SendAndClose(m *PortsDiff) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *portServiceDiffPortsServer) Recv() (*PortEntry, error) {
+	m := new(PortEntry)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PortService_ServiceDesc is the grpc.ServiceDesc for PortService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -133,11 +558,56 @@ var PortService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreatePort",
 			Handler:    _PortService_CreatePort_Handler,
 		},
+		{
+			MethodName: "UpdatePort",
+			Handler:    _PortService_UpdatePort_Handler,
+		},
+		{
+			MethodName: "DeletePort",
+			Handler:    _PortService_DeletePort_Handler,
+		},
+		{
+			MethodName: "GetPort",
+			Handler:    _PortService_GetPort_Handler,
+		},
 		{
 			MethodName: "GetPorts",
 			Handler:    _PortService_GetPorts_Handler,
 		},
+		{
+			MethodName: "ListPorts",
+			Handler:    _PortService_ListPorts_Handler,
+		},
+		{
+			MethodName: "SearchPorts",
+			Handler:    _PortService_SearchPorts_Handler,
+		},
+		{
+			MethodName: "FindNearestPorts",
+			Handler:    _PortService_FindNearestPorts_Handler,
+		},
+		{
+			MethodName: "FindPortsInBoundingBox",
+			Handler:    _PortService_FindPortsInBoundingBox_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPorts",
+			Handler:       _PortService_StreamPorts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamCreatePorts",
+			Handler:       _PortService_StreamCreatePorts_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DiffPorts",
+			Handler:       _PortService_DiffPorts_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "ports.proto",
 }
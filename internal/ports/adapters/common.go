@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+var errNilPort = errors.New("port can't be nil")
+
+// checkWrite verifies that the port can be written and the context isn't done yet.
+func checkWrite(ctx context.Context, port *domainPort.Port) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if port == nil {
+		return errNilPort
+	}
+	return nil
+}
+
+// checkBatchWrite checks every port of the batch before any of them is written.
+func checkBatchWrite(ctx context.Context, ports []*domainPort.Port) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, port := range ports {
+		if port == nil {
+			return errNilPort
+		}
+	}
+	return nil
+}
+
+// storedPort is a port along with its insertion sequence number.
+type storedPort struct {
+	port *domainPort.Port
+	seq  int64
+}
+
+// pageCursor points at the last port of a page. It's handed to clients as an opaque page token.
+type pageCursor struct {
+	OrderBy    domainPort.OrderField `json:"o"`
+	Descending bool                  `json:"d,omitempty"`
+	// Key is a value of the order field of the last port, it's empty when ports are ordered by ID
+	// or insertion time
+	Key string `json:"k,omitempty"`
+	// Seq is an insertion sequence number of the last port, set only when ports are ordered by
+	// insertion time
+	Seq int64  `json:"s,omitempty"`
+	ID  string `json:"i"`
+}
+
+func encodePageToken(cursor pageCursor) string {
+	// marshalling of a struct with only basic types doesn't fail
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// decodePageToken returns cursor from the query page token or nil when query has no token. Token
+// is rejected when it was issued for different ordering than the one requested by the query.
+func decodePageToken(query domainPort.Query) (*pageCursor, error) {
+	if query.PageToken == "" {
+		return nil, nil
+	}
+
+	content, err := base64.RawURLEncoding.DecodeString(query.PageToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainPort.ErrInvalidPageToken, err.Error())
+	}
+	var cursor pageCursor
+	if err = json.Unmarshal(content, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainPort.ErrInvalidPageToken, err.Error())
+	}
+	if cursor.OrderBy != query.OrderBy || cursor.Descending != query.Descending {
+		return nil, fmt.Errorf("%w: token was issued for different order of ports", domainPort.ErrInvalidPageToken)
+	}
+	return &cursor, nil
+}
+
+// orderKey returns value of the field by which ports are ordered, the ID is used
+// as a tie-breaker.
+func orderKey(port *domainPort.Port, field domainPort.OrderField) string {
+	switch field {
+	case domainPort.OrderByName:
+		return port.Name
+	case domainPort.OrderByCountry:
+		return port.Country
+	default:
+		return ""
+	}
+}
+
+// seqKey returns insertion sequence number stored in a cursor, it's needed only when ports
+// are ordered by insertion time.
+func seqKey(stored *storedPort, field domainPort.OrderField) int64 {
+	if field == domainPort.OrderByInsertionTime {
+		return stored.seq
+	}
+	return 0
+}
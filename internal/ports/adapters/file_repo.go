@@ -0,0 +1,516 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+	// snapshot is written to temporary file first and renamed afterwards, so a crash
+	// while writing it never leaves a partially written snapshot behind
+	snapshotTmpFileName = snapshotFileName + ".tmp"
+
+	upsertOperation = "upsert"
+	deleteOperation = "delete"
+	// batchUpsertOperation stores all ports of the record, a torn record is dropped as a whole
+	batchUpsertOperation = "batch_upsert"
+	// replaceOperation stores all ports of the record and deletes the rest, except the kept ones
+	replaceOperation = "replace"
+)
+
+// errBrokenWAL is returned by writes after the write-ahead log couldn't be restored
+// following a failed write. Such repository has to be reopened.
+var errBrokenWAL = fmt.Errorf("%w: write-ahead log is broken, repository has to be reopened",
+	domainPort.ErrUnavailable)
+
+// FileRepo keeps ports in memory and persists every change to a write-ahead log in a local
+// directory before applying it. The log is periodically compacted into a snapshot. When opened,
+// repository state is restored from the snapshot and the changes logged after it.
+//
+// Every log record is a single line prefixed with its checksum, so a record torn by a crash
+// in the middle of a write is detected and dropped on replay.
+type FileRepo struct {
+	// writeMutex serializes writes, so the order of records in the log matches order of changes
+	writeMutex sync.Mutex
+	log        *zap.Logger
+	dir        string
+	mem        *InMemoryRepo
+	wal        *os.File
+	walSize    int64
+	walBroken  bool
+	// seq is a sequence number of the last logged change
+	seq uint64
+}
+
+type walRecord struct {
+	Seq       uint64      `json:"seq"`
+	Operation string      `json:"operation"`
+	Port      *filePort   `json:"port,omitempty"`
+	Ports     []*filePort `json:"ports,omitempty"`
+	KeepIDs   []string    `json:"keep_ids,omitempty"`
+	ID        string      `json:"id,omitempty"`
+}
+
+type snapshot struct {
+	// Seq is a sequence number of the last change included in the snapshot
+	Seq   uint64      `json:"seq"`
+	Ports []*filePort `json:"ports"`
+}
+
+// filePort is a port as written to the files. Files written before ports had a location keep
+// [longitude, latitude] coordinates instead.
+type filePort struct {
+	*domainPort.Port
+	Coordinates []float64 `json:",omitempty"`
+}
+
+// toDomain returns the port, taking its location from legacy coordinates when they're valid.
+func (p *filePort) toDomain() *domainPort.Port {
+	port := p.Port
+	if port == nil {
+		port = &domainPort.Port{}
+	}
+	if port.Location == nil {
+		port.Location = storedLocation(p.Coordinates)
+	}
+	return port
+}
+
+// NewFileRepo opens repository stored in the directory, creating it when it doesn't exist.
+func NewFileRepo(dir string, logger *zap.Logger) (*FileRepo, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create repository directory: %w", err)
+	}
+	// leftover of a snapshot which wasn't completed
+	if err := os.Remove(filepath.Join(dir, snapshotTmpFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove incomplete snapshot: %w", err)
+	}
+
+	r := &FileRepo{
+		log: logger,
+		dir: dir,
+		mem: NewInMemoryRepo(logger),
+	}
+	if err := r.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_RDWR|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	r.wal = wal
+	if err = r.replayWAL(); err != nil {
+		wal.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *FileRepo) CreatePort(ctx context.Context, port *domainPort.Port) error {
+	if err := checkWrite(ctx, port); err != nil {
+		return err
+	}
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	_, err := r.mem.GetPort(ctx, port.ID)
+	if err == nil {
+		return domainPort.ErrAlreadyExists
+	}
+	if !errors.Is(err, domainPort.ErrNotFound) {
+		return err
+	}
+	if err = r.appendRecord(walRecord{Operation: upsertOperation, Port: &filePort{Port: port}}); err != nil {
+		return err
+	}
+	// change is already logged, so it has to be applied even if the context is done by now
+	return r.mem.CreatePort(context.Background(), port)
+}
+
+func (r *FileRepo) UpsertPort(ctx context.Context, port *domainPort.Port) (bool, error) {
+	if err := checkWrite(ctx, port); err != nil {
+		return false, err
+	}
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if err := r.appendRecord(walRecord{Operation: upsertOperation, Port: &filePort{Port: port}}); err != nil {
+		return false, err
+	}
+	return r.mem.UpsertPort(context.Background(), port)
+}
+
+// UpsertPorts logs all the ports in a single record, so either all of them are restored on replay or none.
+func (r *FileRepo) UpsertPorts(ctx context.Context, ports []*domainPort.Port) ([]bool, error) {
+	if err := checkBatchWrite(ctx, ports); err != nil {
+		return nil, err
+	}
+	record := walRecord{Operation: batchUpsertOperation, Ports: filePorts(ports)}
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if err := r.appendRecord(record); err != nil {
+		return nil, err
+	}
+	return r.mem.UpsertPorts(context.Background(), ports)
+}
+
+// ReplacePorts logs the ports together with kept IDs in a single record, replaying it deletes the same
+// ports as the replacement did.
+func (r *FileRepo) ReplacePorts(ctx context.Context, ports []*domainPort.Port, keepIDs []string,
+) ([]bool, []string, error) {
+	if err := checkBatchWrite(ctx, ports); err != nil {
+		return nil, nil, err
+	}
+	record := walRecord{Operation: replaceOperation, Ports: filePorts(ports), KeepIDs: keepIDs}
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if err := r.appendRecord(record); err != nil {
+		return nil, nil, err
+	}
+	return r.mem.ReplacePorts(context.Background(), ports, keepIDs)
+}
+
+func (r *FileRepo) UpdatePort(ctx context.Context, port *domainPort.Port) error {
+	if err := checkWrite(ctx, port); err != nil {
+		return err
+	}
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if _, err := r.mem.GetPort(ctx, port.ID); err != nil {
+		return err
+	}
+	if err := r.appendRecord(walRecord{Operation: upsertOperation, Port: &filePort{Port: port}}); err != nil {
+		return err
+	}
+	return r.mem.UpdatePort(context.Background(), port)
+}
+
+func (r *FileRepo) DeletePort(ctx context.Context, id string) error {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if _, err := r.mem.GetPort(ctx, id); err != nil {
+		return err
+	}
+	if err := r.appendRecord(walRecord{Operation: deleteOperation, ID: id}); err != nil {
+		return err
+	}
+	return r.mem.DeletePort(context.Background(), id)
+}
+
+func (r *FileRepo) GetPort(ctx context.Context, id string) (*domainPort.Port, error) {
+	return r.mem.GetPort(ctx, id)
+}
+
+func (r *FileRepo) GetPorts(ctx context.Context, query domainPort.Query) ([]*domainPort.Port, string, error) {
+	return r.mem.GetPorts(ctx, query)
+}
+
+// RunSnapshots takes a snapshot in every interval until the context is cancelled.
+func (r *FileRepo) RunSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Snapshot(); err != nil {
+				r.log.Error("failed to take snapshot of ports", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Snapshot writes all ports to the snapshot file and truncates the write-ahead log.
+func (r *FileRepo) Snapshot() error {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if r.walSize == 0 {
+		return nil
+	}
+
+	// ports are restored in the order of the snapshot, so it has to keep their insertion order
+	ports, _, err := r.mem.GetPorts(context.Background(), domainPort.Query{OrderBy: domainPort.OrderByInsertionTime})
+	if err != nil {
+		return fmt.Errorf("failed to read ports for snapshot: %w", err)
+	}
+	snap := snapshot{Seq: r.seq, Ports: make([]*filePort, 0, len(ports))}
+	for _, port := range ports {
+		snap.Ports = append(snap.Ports, &filePort{Port: port})
+	}
+	if err = r.writeSnapshot(snap); err != nil {
+		return err
+	}
+
+	// records already included in the snapshot are skipped on replay, so a crash before
+	// the log is truncated leaves repository in a consistent state
+	if err = r.wal.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate write-ahead log: %w", err)
+	}
+	if _, err = r.wal.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind write-ahead log: %w", err)
+	}
+	if err = r.wal.Sync(); err != nil {
+		return fmt.Errorf("failed to sync write-ahead log: %w", err)
+	}
+	r.walSize = 0
+	r.log.Info("snapshot of ports taken", zap.Uint64("seq", r.seq), zap.Int("ports", len(ports)))
+	return nil
+}
+
+// Close takes a final snapshot and closes the write-ahead log.
+func (r *FileRepo) Close() error {
+	snapshotErr := r.Snapshot()
+
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+	if err := r.wal.Close(); err != nil {
+		return fmt.Errorf("failed to close write-ahead log: %w", err)
+	}
+	return snapshotErr
+}
+
+// appendRecord durably appends the record to the write-ahead log. It must be called with
+// write lock held.
+func (r *FileRepo) appendRecord(record walRecord) error {
+	if r.walBroken {
+		return errBrokenWAL
+	}
+
+	record.Seq = r.seq + 1
+	line, err := encodeRecord(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.wal.Write(line)
+	if err == nil {
+		err = r.wal.Sync()
+	}
+	if err != nil {
+		// partially written record would hide every record appended after it on replay
+		if restoreErr := r.restoreWAL(); restoreErr != nil {
+			r.walBroken = true
+			r.log.Error("failed to restore write-ahead log after failed write", zap.Error(restoreErr))
+		}
+		return fmt.Errorf("failed to write to write-ahead log: %w", err)
+	}
+
+	r.walSize += int64(len(line))
+	r.seq = record.Seq
+	return nil
+}
+
+func (r *FileRepo) restoreWAL() error {
+	if err := r.wal.Truncate(r.walSize); err != nil {
+		return fmt.Errorf("failed to truncate write-ahead log: %w", err)
+	}
+	if _, err := r.wal.Seek(r.walSize, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek write-ahead log: %w", err)
+	}
+	return nil
+}
+
+func (r *FileRepo) loadSnapshot() error {
+	content, err := os.ReadFile(filepath.Join(r.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snap snapshot
+	if err = json.Unmarshal(content, &snap); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	for _, stored := range snap.Ports {
+		port := stored.toDomain()
+		if _, err = r.mem.UpsertPort(context.Background(), port); err != nil {
+			return fmt.Errorf("failed to restore port %s from snapshot: %w", port.ID, err)
+		}
+	}
+	r.seq = snap.Seq
+	return nil
+}
+
+func (r *FileRepo) writeSnapshot(snap snapshot) error {
+	tmpPath := filepath.Join(r.dir, snapshotTmpFileName)
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	err = json.NewEncoder(writer).Encode(snap)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, filepath.Join(r.dir, snapshotFileName)); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return syncDir(r.dir)
+}
+
+// replayWAL applies logged changes which aren't included in the snapshot yet. Log is truncated
+// at the first invalid record, which is a record torn by a crash during the write.
+func (r *FileRepo) replayWAL() error {
+	reader := bufio.NewReader(r.wal)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(line) == 0 {
+			break
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read write-ahead log: %w", err)
+		}
+
+		record, decodeErr := decodeRecord(line)
+		if decodeErr != nil {
+			r.log.Warn("dropping invalid tail of write-ahead log",
+				zap.Int64("offset", offset), zap.Error(decodeErr))
+			break
+		}
+		if record.Seq > r.seq {
+			if err = r.applyRecord(record); err != nil {
+				return err
+			}
+			r.seq = record.Seq
+		}
+		offset += int64(len(line))
+	}
+
+	r.walSize = offset
+	if err := r.restoreWAL(); err != nil {
+		return err
+	}
+	return r.wal.Sync()
+}
+
+func (r *FileRepo) applyRecord(record walRecord) error {
+	switch record.Operation {
+	case upsertOperation:
+		if record.Port == nil {
+			return fmt.Errorf("port is missing in write-ahead log record %d", record.Seq)
+		}
+		_, err := r.mem.UpsertPort(context.Background(), record.Port.toDomain())
+		return err
+	case batchUpsertOperation:
+		ports, err := record.domainPorts()
+		if err != nil {
+			return err
+		}
+		_, err = r.mem.UpsertPorts(context.Background(), ports)
+		return err
+	case replaceOperation:
+		ports, err := record.domainPorts()
+		if err != nil {
+			return err
+		}
+		_, _, err = r.mem.ReplacePorts(context.Background(), ports, record.KeepIDs)
+		return err
+	case deleteOperation:
+		err := r.mem.DeletePort(context.Background(), record.ID)
+		if errors.Is(err, domainPort.ErrNotFound) {
+			return nil
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown operation %q in write-ahead log record %d", record.Operation, record.Seq)
+	}
+}
+
+// domainPorts returns ports of a batch record.
+func (record walRecord) domainPorts() ([]*domainPort.Port, error) {
+	ports := make([]*domainPort.Port, len(record.Ports))
+	for i, port := range record.Ports {
+		if port == nil {
+			return nil, fmt.Errorf("port %d is missing in write-ahead log record %d", i, record.Seq)
+		}
+		ports[i] = port.toDomain()
+	}
+	return ports, nil
+}
+
+func filePorts(ports []*domainPort.Port) []*filePort {
+	stored := make([]*filePort, len(ports))
+	for i, port := range ports {
+		stored[i] = &filePort{Port: port}
+	}
+	return stored
+}
+
+// encodeRecord encodes record as a line with crc32 checksum of the json payload, i.e.
+// "1c291ca3 {...}\n"
+func encodeRecord(record walRecord) ([]byte, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode write-ahead log record: %w", err)
+	}
+	line := make([]byte, 0, len(payload)+10)
+	line = append(line, fmt.Sprintf("%08x ", crc32.ChecksumIEEE(payload))...)
+	line = append(line, payload...)
+	return append(line, '\n'), nil
+}
+
+func decodeRecord(line []byte) (walRecord, error) {
+	var record walRecord
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		return record, errors.New("record is incomplete")
+	}
+	checksum, payload, found := bytes.Cut(bytes.TrimSuffix(line, []byte{'\n'}), []byte{' '})
+	if !found {
+		return record, errors.New("record has no checksum")
+	}
+	expected, err := strconv.ParseUint(string(checksum), 16, 32)
+	if err != nil {
+		return record, fmt.Errorf("invalid record checksum: %w", err)
+	}
+	if uint32(expected) != crc32.ChecksumIEEE(payload) {
+		return record, errors.New("record checksum mismatch")
+	}
+	if err = json.Unmarshal(payload, &record); err != nil {
+		return record, fmt.Errorf("failed to decode record: %w", err)
+	}
+	return record, nil
+}
+
+// syncDir makes sure that changes of directory entries, like renames, are persisted.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open directory: %w", err)
+	}
+	defer d.Close()
+	if err = d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync directory: %w", err)
+	}
+	return nil
+}
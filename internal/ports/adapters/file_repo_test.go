@@ -0,0 +1,294 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/arturskrzydlo/ports/internal/ports"
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+	"github.com/arturskrzydlo/ports/internal/ports/repositorytest"
+)
+
+type fileRepoSuite struct {
+	suite.Suite
+
+	log *zap.Logger
+	dir string
+}
+
+func TestFileRepo(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) ports.Repository {
+		repo, err := NewFileRepo(t.TempDir(), zap.NewNop())
+		require.NoError(t, err)
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+func TestFileRepoRecovery(t *testing.T) {
+	suite.Run(t, &fileRepoSuite{})
+}
+
+func (s *fileRepoSuite) SetupSuite() {
+	log, err := zap.NewDevelopment()
+	s.Require().NoError(err)
+	s.log = log
+}
+
+func (s *fileRepoSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *fileRepoSuite) TestReplayingWriteAheadLog() {
+	// given
+	repo := s.openRepo()
+	s.storeChanges(repo)
+	s.Require().NoError(repo.wal.Close())
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	s.assertChangesRestored(repo)
+}
+
+func (s *fileRepoSuite) TestRestoringSnapshotAndWriteAheadLog() {
+	// given
+	repo := s.openRepo()
+	s.storeChanges(repo)
+	s.Require().NoError(repo.Snapshot())
+	walInfo, err := os.Stat(filepath.Join(s.dir, walFileName))
+	s.Require().NoError(err)
+	s.Require().Zero(walInfo.Size())
+
+	s.Require().NoError(repo.CreatePort(context.Background(), s.createPort("AEDXB")))
+	s.Require().NoError(repo.wal.Close())
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	s.assertChangesRestored(repo)
+	_, err = repo.GetPort(context.Background(), "AEDXB")
+	s.Assert().NoError(err)
+}
+
+func (s *fileRepoSuite) TestSkippingLogRecordsIncludedInSnapshot() {
+	// given crash after the snapshot was written but before the log was truncated
+	repo := s.openRepo()
+	s.storeChanges(repo)
+	walContent, err := os.ReadFile(filepath.Join(s.dir, walFileName))
+	s.Require().NoError(err)
+	s.Require().NoError(repo.Snapshot())
+	s.Require().NoError(repo.wal.Close())
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, walFileName), walContent, 0o640))
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	s.assertChangesRestored(repo)
+	s.Assert().Equal(uint64(4), repo.seq)
+}
+
+func (s *fileRepoSuite) TestRecoveringFromTornWrite() {
+	// given crash in the middle of writing a record
+	repo := s.openRepo()
+	s.storeChanges(repo)
+	s.Require().NoError(repo.wal.Close())
+	line, err := encodeRecord(walRecord{Seq: 5, Operation: upsertOperation, Port: &filePort{Port: s.createPort("AEDXB")}})
+	s.Require().NoError(err)
+	wal, err := os.OpenFile(filepath.Join(s.dir, walFileName), os.O_WRONLY|os.O_APPEND, 0o640)
+	s.Require().NoError(err)
+	_, err = wal.Write(line[:len(line)/2])
+	s.Require().NoError(err)
+	s.Require().NoError(wal.Close())
+
+	// when
+	repo = s.openRepo()
+
+	// then torn record is dropped and new records are readable after it
+	s.assertChangesRestored(repo)
+	_, err = repo.GetPort(context.Background(), "AEDXB")
+	s.Assert().ErrorIs(err, domainPort.ErrNotFound)
+
+	s.Require().NoError(repo.CreatePort(context.Background(), s.createPort("AEDXB")))
+	s.Require().NoError(repo.wal.Close())
+	repo = s.openRepo()
+	_, err = repo.GetPort(context.Background(), "AEDXB")
+	s.Assert().NoError(err)
+}
+
+func (s *fileRepoSuite) TestReplayingBatchAsAWhole() {
+	// given
+	repo := s.openRepo()
+	_, err := repo.UpsertPorts(context.Background(), []*domainPort.Port{s.createPort("AEAJM"), s.createPort("AEAUH")})
+	s.Require().NoError(err)
+	line, err := encodeRecord(walRecord{Seq: 2, Operation: batchUpsertOperation, Ports: []*filePort{
+		{Port: s.createPort("AEDXB")}, {Port: s.createPort("AEKLF")},
+	}})
+	s.Require().NoError(err)
+	// crash in the middle of writing the second batch
+	_, err = repo.wal.Write(line[:len(line)-10])
+	s.Require().NoError(err)
+	s.Require().NoError(repo.wal.Close())
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	ports, _, err := repo.GetPorts(context.Background(), domainPort.Query{})
+	s.Require().NoError(err)
+	s.Require().Len(ports, 2)
+	s.Assert().Equal("AEAJM", ports[0].ID)
+	s.Assert().Equal("AEAUH", ports[1].ID)
+}
+
+func (s *fileRepoSuite) TestReplayingReplacement() {
+	// given
+	repo := s.openRepo()
+	_, err := repo.UpsertPorts(context.Background(), []*domainPort.Port{s.createPort("AEAJM"), s.createPort("AEAUH")})
+	s.Require().NoError(err)
+	_, _, err = repo.ReplacePorts(context.Background(), []*domainPort.Port{s.createPort("AEDXB")}, []string{"AEAUH"})
+	s.Require().NoError(err)
+	// crash before snapshot was taken
+	s.Require().NoError(repo.wal.Close())
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	ports, _, err := repo.GetPorts(context.Background(), domainPort.Query{})
+	s.Require().NoError(err)
+	s.Require().Len(ports, 2)
+	s.Assert().Equal("AEAUH", ports[0].ID)
+	s.Assert().Equal("AEDXB", ports[1].ID)
+}
+
+func (s *fileRepoSuite) TestRemovingIncompleteSnapshot() {
+	// given crash while snapshot was being written
+	repo := s.openRepo()
+	s.storeChanges(repo)
+	s.Require().NoError(repo.wal.Close())
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, snapshotTmpFileName), []byte(`{"seq": 1, "por`), 0o640))
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	s.assertChangesRestored(repo)
+	s.Assert().NoFileExists(filepath.Join(s.dir, snapshotTmpFileName))
+}
+
+func (s *fileRepoSuite) TestClosingTakesSnapshot() {
+	// given
+	repo := s.openRepo()
+	s.storeChanges(repo)
+
+	// when
+	s.Require().NoError(repo.Close())
+
+	// then
+	s.Assert().FileExists(filepath.Join(s.dir, snapshotFileName))
+	s.assertChangesRestored(s.openRepo())
+}
+
+func (s *fileRepoSuite) TestRestoringInsertionOrder() {
+	// given
+	repo := s.openRepo()
+	for _, id := range []string{"AEKLF", "AEAJM", "AEDXB"} {
+		s.Require().NoError(repo.CreatePort(context.Background(), s.createPort(id)))
+	}
+	s.Require().NoError(repo.Close())
+
+	// when
+	repo = s.openRepo()
+
+	// then
+	ports, _, err := repo.GetPorts(context.Background(), domainPort.Query{OrderBy: domainPort.OrderByInsertionTime})
+	s.Require().NoError(err)
+	s.Require().Len(ports, 3)
+	s.Assert().Equal("AEKLF", ports[0].ID)
+	s.Assert().Equal("AEAJM", ports[1].ID)
+	s.Assert().Equal("AEDXB", ports[2].ID)
+}
+
+func (s *fileRepoSuite) TestReadingLegacyCoordinates() {
+	// given files written before ports had a location
+	snapshotContent := `{"seq":1,"ports":[{"ID":"AEAJM","Coordinates":[55.5136433,25.4052165]},` +
+		`{"ID":"XXINV","Coordinates":[90]}]}`
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, snapshotFileName), []byte(snapshotContent), 0o640))
+	line, err := encodeRecord(walRecord{Seq: 2, Operation: upsertOperation, Port: &filePort{
+		Port:        &domainPort.Port{ID: "AEAUH"},
+		Coordinates: []float64{54.37, 24.47},
+	}})
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, walFileName), line, 0o640))
+
+	// when
+	repo := s.openRepo()
+
+	// then
+	port, err := repo.GetPort(context.Background(), "AEAJM")
+	s.Require().NoError(err)
+	s.Assert().Equal(&domainPort.GeoPoint{Lat: 25.4052165, Lon: 55.5136433}, port.Location)
+	port, err = repo.GetPort(context.Background(), "AEAUH")
+	s.Require().NoError(err)
+	s.Assert().Equal(&domainPort.GeoPoint{Lat: 24.47, Lon: 54.37}, port.Location)
+	// invalid coordinates are dropped
+	port, err = repo.GetPort(context.Background(), "XXINV")
+	s.Require().NoError(err)
+	s.Assert().Nil(port.Location)
+}
+
+func (s *fileRepoSuite) openRepo() *FileRepo {
+	repo, err := NewFileRepo(s.dir, s.log)
+	s.Require().NoError(err)
+	return repo
+}
+
+// storeChanges makes 4 changes, after which only updated AEAJM port is left.
+func (s *fileRepoSuite) storeChanges(repo *FileRepo) {
+	ctx := context.Background()
+	s.Require().NoError(repo.CreatePort(ctx, s.createPort("AEAJM")))
+	s.Require().NoError(repo.CreatePort(ctx, s.createPort("AEAUH")))
+	updatedPort := s.createPort("AEAJM")
+	updatedPort.Name = "updated-name"
+	created, err := repo.UpsertPort(ctx, updatedPort)
+	s.Require().NoError(err)
+	s.Require().False(created)
+	s.Require().NoError(repo.DeletePort(ctx, "AEAUH"))
+}
+
+func (s *fileRepoSuite) assertChangesRestored(repo *FileRepo) {
+	port, err := repo.GetPort(context.Background(), "AEAJM")
+	s.Require().NoError(err)
+	expectedPort := s.createPort("AEAJM")
+	expectedPort.Name = "updated-name"
+	s.Assert().Equal(expectedPort, port)
+
+	_, err = repo.GetPort(context.Background(), "AEAUH")
+	s.Assert().ErrorIs(err, domainPort.ErrNotFound)
+}
+
+func (s *fileRepoSuite) createPort(id string) *domainPort.Port {
+	return &domainPort.Port{
+		ID:       id,
+		Name:     "name",
+		City:     "city",
+		Country:  "United Arab Emirates",
+		Alias:    []string{"alias"},
+		Regions:  []string{"region"},
+		Location: &domainPort.GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+		Province: "province",
+		Timezone: "Asia/Dubai",
+		Unlocs:   []string{id},
+		Code:     "52000",
+	}
+}
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"go.uber.org/zap"
@@ -12,31 +13,258 @@ import (
 type InMemoryRepo struct {
 	mutex   sync.RWMutex
 	log     *zap.Logger
-	storage map[string]*domainPort.Port
+	storage map[string]*storedPort
+	// lastSeq is an insertion sequence number of the most recently created port
+	lastSeq int64
+
+	// viewsMutex guards views, which are built lazily by readers holding read lock
+	viewsMutex sync.Mutex
+	// views holds ports sorted in ascending order by each of order fields, so pages can be served
+	// without sorting the whole storage. Views are dropped on every write.
+	views map[domainPort.OrderField][]*storedPort
 }
 
 func NewInMemoryRepo(logger *zap.Logger) *InMemoryRepo {
 	return &InMemoryRepo{
 		log:     logger,
-		storage: make(map[string]*domainPort.Port),
+		storage: make(map[string]*storedPort),
+	}
+}
+
+func (r *InMemoryRepo) CreatePort(ctx context.Context, port *domainPort.Port) error {
+	if err := checkWrite(ctx, port); err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.storage[port.ID]; exists {
+		return domainPort.ErrAlreadyExists
+	}
+	r.store(port)
+	return nil
+}
+
+func (r *InMemoryRepo) UpsertPort(ctx context.Context, port *domainPort.Port) (bool, error) {
+	if err := checkWrite(ctx, port); err != nil {
+		return false, err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, exists := r.storage[port.ID]
+	r.store(port)
+	return !exists, nil
+}
+
+func (r *InMemoryRepo) UpsertPorts(ctx context.Context, ports []*domainPort.Port) ([]bool, error) {
+	if err := checkBatchWrite(ctx, ports); err != nil {
+		return nil, err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	created := make([]bool, len(ports))
+	for i, port := range ports {
+		_, exists := r.storage[port.ID]
+		r.store(port)
+		created[i] = !exists
+	}
+	return created, nil
+}
+
+func (r *InMemoryRepo) ReplacePorts(ctx context.Context, ports []*domainPort.Port, keepIDs []string,
+) ([]bool, []string, error) {
+	if err := checkBatchWrite(ctx, ports); err != nil {
+		return nil, nil, err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.replace(ports, keepIDs)
+}
+
+// replace stores the ports and deletes all other ports not listed in keepIDs. It must be called with
+// write lock held.
+func (r *InMemoryRepo) replace(ports []*domainPort.Port, keepIDs []string) ([]bool, []string, error) {
+	kept := make(map[string]struct{}, len(ports)+len(keepIDs))
+	for _, id := range keepIDs {
+		kept[id] = struct{}{}
+	}
+	created := make([]bool, len(ports))
+	for i, port := range ports {
+		_, exists := r.storage[port.ID]
+		r.store(port)
+		created[i] = !exists
+		kept[port.ID] = struct{}{}
+	}
+
+	deleted := []string{}
+	for id := range r.storage {
+		if _, ok := kept[id]; !ok {
+			deleted = append(deleted, id)
+			delete(r.storage, id)
+		}
+	}
+	sort.Strings(deleted)
+	r.views = nil
+	return created, deleted, nil
+}
+
+func (r *InMemoryRepo) UpdatePort(ctx context.Context, port *domainPort.Port) error {
+	if err := checkWrite(ctx, port); err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.storage[port.ID]; !exists {
+		return domainPort.ErrNotFound
 	}
+	r.store(port)
+	return nil
 }
 
-func (r *InMemoryRepo) CreatePort(_ context.Context, port *domainPort.Port) error {
+func (r *InMemoryRepo) DeletePort(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	r.storage[port.ID] = port
+	if _, exists := r.storage[id]; !exists {
+		return domainPort.ErrNotFound
+	}
+	delete(r.storage, id)
+	r.views = nil
 	return nil
 }
 
-func (r *InMemoryRepo) GetPorts(_ context.Context) ([]*domainPort.Port, error) {
+func (r *InMemoryRepo) GetPort(ctx context.Context, id string) (*domainPort.Port, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
+	stored, ok := r.storage[id]
+	if !ok {
+		return nil, domainPort.ErrNotFound
+	}
+	return stored.port, nil
+}
+
+func (r *InMemoryRepo) GetPorts(ctx context.Context, query domainPort.Query) ([]*domainPort.Port, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	cursor, err := decodePageToken(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	view := r.view(query.OrderBy)
+
+	// view is in ascending order, descending pages are read from its end backwards
+	next := func(i int) int { return i + 1 }
+	start := 0
+	if query.Descending {
+		next = func(i int) int { return i - 1 }
+		start = len(view) - 1
+	}
+	if cursor != nil {
+		lastPort := cursorPort(cursor, query.OrderBy)
+		if query.Descending {
+			// index of the last port ordered before the cursor
+			start = sort.Search(len(view), func(i int) bool {
+				return !less(view[i], lastPort, query.OrderBy)
+			}) - 1
+		} else {
+			// index of the first port ordered after the cursor
+			start = sort.Search(len(view), func(i int) bool {
+				return less(lastPort, view[i], query.OrderBy)
+			})
+		}
+	}
+
 	ports := make([]*domainPort.Port, 0)
-	for _, storagePort := range r.storage {
-		if storagePort != nil {
-			ports = append(ports, storagePort)
+	var last *storedPort
+	i := start
+	hasNextPage := false
+	for ; i >= 0 && i < len(view); i = next(i) {
+		if !query.Filter.Matches(view[i].port) {
+			continue
 		}
+		if query.PageSize > 0 && len(ports) == query.PageSize {
+			hasNextPage = true
+			break
+		}
+		last = view[i]
+		ports = append(ports, last.port)
+	}
+
+	nextPageToken := ""
+	if hasNextPage {
+		nextPageToken = encodePageToken(pageCursor{
+			OrderBy:    query.OrderBy,
+			Descending: query.Descending,
+			Key:        orderKey(last.port, query.OrderBy),
+			Seq:        seqKey(last, query.OrderBy),
+			ID:         last.port.ID,
+		})
+	}
+	return ports, nextPageToken, nil
+}
+
+// store saves the port keeping insertion sequence number of already existing port.
+// It must be called with write lock held.
+func (r *InMemoryRepo) store(port *domainPort.Port) {
+	if stored, exists := r.storage[port.ID]; exists {
+		stored.port = port
+	} else {
+		r.lastSeq++
+		r.storage[port.ID] = &storedPort{port: port, seq: r.lastSeq}
+	}
+	r.views = nil
+}
+
+// view returns all ports sorted in ascending order by the field. It must be called with
+// read lock held.
+func (r *InMemoryRepo) view(field domainPort.OrderField) []*storedPort {
+	r.viewsMutex.Lock()
+	defer r.viewsMutex.Unlock()
+	if view, ok := r.views[field]; ok {
+		return view
+	}
+
+	view := make([]*storedPort, 0, len(r.storage))
+	for _, stored := range r.storage {
+		view = append(view, stored)
+	}
+	sort.Slice(view, func(i, j int) bool { return less(view[i], view[j], field) })
+
+	if r.views == nil {
+		r.views = make(map[domainPort.OrderField][]*storedPort)
+	}
+	r.views[field] = view
+	return view
+}
+
+func less(a, b *storedPort, field domainPort.OrderField) bool {
+	if field == domainPort.OrderByInsertionTime {
+		return a.seq < b.seq
+	}
+	aKey, bKey := orderKey(a.port, field), orderKey(b.port, field)
+	if aKey != bKey {
+		return aKey < bKey
+	}
+	return a.port.ID < b.port.ID
+}
+
+// cursorPort recreates the last port of previous page from the cursor, only with fields
+// needed to compare it with stored ports.
+func cursorPort(cursor *pageCursor, field domainPort.OrderField) *storedPort {
+	port := &domainPort.Port{ID: cursor.ID}
+	switch field {
+	case domainPort.OrderByName:
+		port.Name = cursor.Key
+	case domainPort.OrderByCountry:
+		port.Country = cursor.Key
 	}
-	return ports, nil
+	return &storedPort{port: port, seq: cursor.Seq}
 }
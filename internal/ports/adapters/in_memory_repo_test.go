@@ -0,0 +1,16 @@
+package adapters
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/arturskrzydlo/ports/internal/ports"
+	"github.com/arturskrzydlo/ports/internal/ports/repositorytest"
+)
+
+func TestInMemoryRepo(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) ports.Repository {
+		return NewInMemoryRepo(zap.NewNop())
+	})
+}
@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLockID is a key of advisory lock which prevents concurrent migrations
+// when several instances of ports service start at once.
+const migrationsLockID = 8090
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrate applies all embedded migrations which weren't applied yet. Every migration
+// is applied in its own transaction together with bumping the schema version.
+func migrate(ctx context.Context, pool *pgxpool.Pool, log *zap.Logger) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID); unlockErr != nil {
+			log.Warn("failed to unlock migrations", zap.Error(unlockErr))
+		}
+	}()
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations
+		(
+			version    INT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema migrations table: %w", err)
+	}
+
+	var currentVersion int
+	err = conn.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&currentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= currentVersion {
+			continue
+		}
+		log.Info("applying migration", zap.String("migration", m.name))
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, execErr := tx.Exec(ctx, m.sql); execErr != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", m.name, execErr)
+			}
+			if _, execErr := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version); execErr != nil {
+				return fmt.Errorf("failed to store version of migration %s: %w", m.name, execErr)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMigrations reads embedded migrations sorted by version. Migration files are
+// named <version>_<description>.sql.
+func loadMigrations() ([]migration, error) {
+	files, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		rawVersion, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s has no version prefix", name)
+		}
+		version, err := strconv.Atoi(rawVersion)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has invalid version: %w", name, err)
+		}
+		sql, err := migrationsFS.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		migrations = append(migrations, migration{version: version, name: name, sql: string(sql)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
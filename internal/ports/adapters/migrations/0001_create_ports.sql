@@ -0,0 +1,14 @@
+CREATE TABLE ports
+(
+    id          TEXT PRIMARY KEY,
+    name        TEXT               NOT NULL DEFAULT '',
+    city        TEXT               NOT NULL DEFAULT '',
+    country     TEXT               NOT NULL DEFAULT '',
+    alias       TEXT[]             NOT NULL DEFAULT '{}',
+    regions     TEXT[]             NOT NULL DEFAULT '{}',
+    coordinates DOUBLE PRECISION[] NOT NULL DEFAULT '{}',
+    province    TEXT               NOT NULL DEFAULT '',
+    timezone    TEXT               NOT NULL DEFAULT '',
+    unlocs      TEXT[]             NOT NULL DEFAULT '{}',
+    code        TEXT               NOT NULL
+);
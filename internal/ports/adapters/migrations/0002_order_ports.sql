@@ -0,0 +1,13 @@
+-- byte-wise collation makes the order of ports the same as in other repositories
+ALTER TABLE ports
+    ALTER COLUMN id TYPE TEXT COLLATE "C",
+    ALTER COLUMN name TYPE TEXT COLLATE "C",
+    ALTER COLUMN country TYPE TEXT COLLATE "C";
+
+-- seq keeps the order in which ports were inserted, upserts don't change it
+ALTER TABLE ports
+    ADD COLUMN seq BIGSERIAL NOT NULL;
+
+CREATE INDEX ports_name_idx ON ports (name, id);
+CREATE INDEX ports_country_idx ON ports (country, id);
+CREATE UNIQUE INDEX ports_seq_idx ON ports (seq);
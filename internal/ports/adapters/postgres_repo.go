@@ -0,0 +1,451 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+// uniqueViolationCode is postgres error code raised when unique constraint is violated
+const uniqueViolationCode = "23505"
+
+// unavailableCodeClasses are classes of postgres error codes raised when database can't serve requests
+// at the moment, i.e. 08 is a connection exception and 57 is operator intervention like shutdown
+var unavailableCodeClasses = []string{"08", "53", "57"}
+
+const portColumns = "id, name, city, country, alias, regions, coordinates, province, timezone, unlocs, code"
+
+type PostgresRepo struct {
+	pool *pgxpool.Pool
+	log  *zap.Logger
+}
+
+// NewPostgresRepo connects to the database and migrates its schema to the latest version.
+func NewPostgresRepo(ctx context.Context, dsn string, logger *zap.Logger) (*PostgresRepo, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres connection pool: %w", err)
+	}
+
+	if err = migrate(ctx, pool, logger); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to migrate postgres schema: %w", err)
+	}
+
+	return &PostgresRepo{
+		pool: pool,
+		log:  logger,
+	}, nil
+}
+
+// Close closes all connections to the database.
+func (r *PostgresRepo) Close() {
+	r.pool.Close()
+}
+
+func (r *PostgresRepo) CreatePort(ctx context.Context, port *domainPort.Port) error {
+	if err := checkWrite(ctx, port); err != nil {
+		return err
+	}
+	_, err := r.pool.Exec(ctx, `INSERT INTO ports (`+portColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`, portValues(port)...)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return domainPort.ErrAlreadyExists
+	}
+	if err != nil {
+		return queryErr("failed to insert port", err)
+	}
+	return nil
+}
+
+// upsertPortQuery inserts a port or updates the existing one. It returns whether the port was created,
+// as xmax of a row is zero unless the row was updated.
+const upsertPortQuery = `INSERT INTO ports (` + portColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			city = EXCLUDED.city,
+			country = EXCLUDED.country,
+			alias = EXCLUDED.alias,
+			regions = EXCLUDED.regions,
+			coordinates = EXCLUDED.coordinates,
+			province = EXCLUDED.province,
+			timezone = EXCLUDED.timezone,
+			unlocs = EXCLUDED.unlocs,
+			code = EXCLUDED.code
+		RETURNING (xmax = 0)`
+
+func (r *PostgresRepo) UpsertPort(ctx context.Context, port *domainPort.Port) (bool, error) {
+	if err := checkWrite(ctx, port); err != nil {
+		return false, err
+	}
+	var created bool
+	if err := r.pool.QueryRow(ctx, upsertPortQuery, portValues(port)...).Scan(&created); err != nil {
+		return false, queryErr("failed to upsert port", err)
+	}
+	return created, nil
+}
+
+// UpsertPorts upserts the ports in a single transaction, queries are sent in one batch.
+func (r *PostgresRepo) UpsertPorts(ctx context.Context, ports []*domainPort.Port) ([]bool, error) {
+	if err := checkBatchWrite(ctx, ports); err != nil {
+		return nil, err
+	}
+	created := make([]bool, len(ports))
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		queueUpserts(batch, ports, created)
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return nil, queryErr("failed to upsert ports", err)
+	}
+	return created, nil
+}
+
+// ReplacePorts upserts the ports and deletes the rest in a single transaction, queries are sent in one batch.
+func (r *PostgresRepo) ReplacePorts(ctx context.Context, ports []*domainPort.Port, keepIDs []string,
+) ([]bool, []string, error) {
+	if err := checkBatchWrite(ctx, ports); err != nil {
+		return nil, nil, err
+	}
+	kept := append([]string{}, keepIDs...)
+	for _, port := range ports {
+		kept = append(kept, port.ID)
+	}
+
+	var (
+		created = make([]bool, len(ports))
+		deleted []string
+	)
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		queueUpserts(batch, ports, created)
+		batch.Queue("DELETE FROM ports WHERE NOT (id = ANY($1)) RETURNING id", kept).Query(func(rows pgx.Rows) error {
+			var err error
+			deleted, err = pgx.CollectRows(rows, pgx.RowTo[string])
+			return err
+		})
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return nil, nil, queryErr("failed to replace ports", err)
+	}
+	sort.Strings(deleted)
+	return created, deleted, nil
+}
+
+// queueUpserts adds upsert of every port to the batch, created flags are scanned into created.
+func queueUpserts(batch *pgx.Batch, ports []*domainPort.Port, created []bool) {
+	for i, port := range ports {
+		i := i
+		batch.Queue(upsertPortQuery, portValues(port)...).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&created[i])
+		})
+	}
+}
+
+func (r *PostgresRepo) UpdatePort(ctx context.Context, port *domainPort.Port) error {
+	if err := checkWrite(ctx, port); err != nil {
+		return err
+	}
+	tag, err := r.pool.Exec(ctx, `UPDATE ports SET
+			name = $2,
+			city = $3,
+			country = $4,
+			alias = $5,
+			regions = $6,
+			coordinates = $7,
+			province = $8,
+			timezone = $9,
+			unlocs = $10,
+			code = $11
+		WHERE id = $1`, portValues(port)...)
+	if err != nil {
+		return queryErr("failed to update port", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domainPort.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepo) DeletePort(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM ports WHERE id = $1", id)
+	if err != nil {
+		return queryErr("failed to delete port", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domainPort.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepo) GetPort(ctx context.Context, id string) (*domainPort.Port, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+portColumns+" FROM ports WHERE id = $1", id)
+	if err != nil {
+		return nil, queryErr("failed to query port", err)
+	}
+	port, err := pgx.CollectOneRow(rows, scanPort)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domainPort.ErrNotFound
+	}
+	if err != nil {
+		return nil, queryErr("failed to read port", err)
+	}
+	return port, nil
+}
+
+func (r *PostgresRepo) GetPorts(ctx context.Context, query domainPort.Query) ([]*domainPort.Port, string, error) {
+	cursor, err := decodePageToken(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	orderColumns := orderColumns(query.OrderBy)
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	var conditions sqlConditions
+	if cursor != nil {
+		// row comparison continues right after the last port of previous page
+		lastValues := make([]string, 0, len(orderColumns))
+		for _, value := range cursorValues(cursor, query.OrderBy) {
+			lastValues = append(lastValues, conditions.arg(value))
+		}
+		conditions.add("(" + strings.Join(orderColumns, ", ") + ") " + comparison + " (" + strings.Join(lastValues, ", ") + ")")
+	}
+	addFilterConditions(&conditions, query.Filter)
+
+	sql := "SELECT " + portColumns + ", seq FROM ports" + conditions.where() +
+		" ORDER BY " + strings.Join(orderColumns, " "+direction+", ") + " " + direction
+	// one more port is fetched to find out if there is a next page
+	if query.PageSize > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", query.PageSize+1)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, conditions.args...)
+	if err != nil {
+		return nil, "", queryErr("failed to query ports", err)
+	}
+	sequencedPorts, err := pgx.CollectRows(rows, scanSequencedPort)
+	if err != nil {
+		return nil, "", queryErr("failed to read ports", err)
+	}
+
+	nextPageToken := ""
+	if query.PageSize > 0 && len(sequencedPorts) > query.PageSize {
+		sequencedPorts = sequencedPorts[:query.PageSize]
+		last := sequencedPorts[len(sequencedPorts)-1]
+		nextPageToken = encodePageToken(pageCursor{
+			OrderBy:    query.OrderBy,
+			Descending: query.Descending,
+			Key:        orderKey(last.port, query.OrderBy),
+			Seq:        seqKey(last, query.OrderBy),
+			ID:         last.port.ID,
+		})
+	}
+
+	ports := make([]*domainPort.Port, len(sequencedPorts))
+	for i, sequencedPort := range sequencedPorts {
+		ports[i] = sequencedPort.port
+	}
+	return ports, nextPageToken, nil
+}
+
+// orderColumns returns columns by which ports are sorted for given order field.
+func orderColumns(field domainPort.OrderField) []string {
+	switch field {
+	case domainPort.OrderByName:
+		return []string{"name", "id"}
+	case domainPort.OrderByCountry:
+		return []string{"country", "id"}
+	case domainPort.OrderByInsertionTime:
+		return []string{"seq"}
+	default:
+		return []string{"id"}
+	}
+}
+
+// cursorValues returns values of the cursor in the order of orderColumns.
+func cursorValues(cursor *pageCursor, field domainPort.OrderField) []any {
+	switch field {
+	case domainPort.OrderByName, domainPort.OrderByCountry:
+		return []any{cursor.Key, cursor.ID}
+	case domainPort.OrderByInsertionTime:
+		return []any{cursor.Seq}
+	default:
+		return []any{cursor.ID}
+	}
+}
+
+// sqlConditions collects conditions of WHERE clause along with their arguments.
+type sqlConditions struct {
+	conditions []string
+	args       []any
+}
+
+// arg adds the argument and returns its placeholder.
+func (c *sqlConditions) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *sqlConditions) add(condition string) {
+	c.conditions = append(c.conditions, condition)
+}
+
+func (c *sqlConditions) where() string {
+	if len(c.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.conditions, " AND ")
+}
+
+// addFilterConditions adds conditions matching the same ports as domainPort.Filter.Matches.
+func addFilterConditions(conditions *sqlConditions, filter domainPort.Filter) {
+	// columns are listed in fixed order, so the same filter always produces the same statement
+	for _, field := range []struct{ column, value string }{
+		{column: "country", value: filter.Country},
+		{column: "province", value: filter.Province},
+		{column: "city", value: filter.City},
+		{column: "timezone", value: filter.Timezone},
+		{column: "code", value: filter.Code},
+	} {
+		if field.value != "" {
+			conditions.add("lower(" + field.column + ") = lower(" + conditions.arg(field.value) + ")")
+		}
+	}
+	for _, field := range []struct{ column, value string }{
+		{column: "regions", value: filter.Region},
+		{column: "unlocs", value: filter.Unloc},
+	} {
+		if field.value != "" {
+			conditions.add("lower(" + conditions.arg(field.value) + ") IN " +
+				"(SELECT lower(value) FROM unnest(" + field.column + ") AS value)")
+		}
+	}
+}
+
+// portValues returns values of port columns in the order of portColumns.
+func portValues(port *domainPort.Port) []any {
+	return []any{
+		port.ID,
+		port.Name,
+		port.City,
+		port.Country,
+		nonNilStrings(port.Alias),
+		nonNilStrings(port.Regions),
+		nonNilFloats(port.Location.Coordinates()),
+		port.Province,
+		port.Timezone,
+		nonNilStrings(port.Unlocs),
+		port.Code,
+	}
+}
+
+func scanPort(row pgx.CollectableRow) (*domainPort.Port, error) {
+	var (
+		port        domainPort.Port
+		coordinates []float64
+	)
+	if err := row.Scan(portFields(&port, &coordinates)...); err != nil {
+		return nil, err
+	}
+	port.Location = storedLocation(coordinates)
+	return &port, nil
+}
+
+// scanSequencedPort scans port columns followed by the insertion sequence number.
+func scanSequencedPort(row pgx.CollectableRow) (*storedPort, error) {
+	var coordinates []float64
+	stored := storedPort{port: &domainPort.Port{}}
+	if err := row.Scan(append(portFields(stored.port, &coordinates), &stored.seq)...); err != nil {
+		return nil, err
+	}
+	stored.port.Location = storedLocation(coordinates)
+	return &stored, nil
+}
+
+// storedLocation reads location from [longitude, latitude] column. Coordinates stored before they
+// were validated may be invalid, such ports are read without location.
+func storedLocation(coordinates []float64) *domainPort.GeoPoint {
+	location, err := domainPort.GeoPointFromCoordinates(coordinates)
+	if err != nil {
+		return nil
+	}
+	return location
+}
+
+// portFields returns pointers to port fields in the order of portColumns. Coordinates column is
+// scanned separately, as port keeps them as a location.
+func portFields(port *domainPort.Port, coordinates *[]float64) []any {
+	return []any{
+		&port.ID,
+		&port.Name,
+		&port.City,
+		&port.Country,
+		&port.Alias,
+		&port.Regions,
+		coordinates,
+		&port.Province,
+		&port.Timezone,
+		&port.Unlocs,
+		&port.Code,
+	}
+}
+
+// queryErr wraps error of a database call, marking it with domainPort.ErrUnavailable when database
+// can't be reached at the moment.
+func queryErr(message string, err error) error {
+	if isUnavailable(err) {
+		return fmt.Errorf("%s: %w: %w", message, domainPort.ErrUnavailable, err)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func isUnavailable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		for _, class := range unavailableCodeClasses {
+			if strings.HasPrefix(pgErr.Code, class) {
+				return true
+			}
+		}
+		return false
+	}
+	// errors of connections which couldn't be established or were broken, including timeouts
+	var netErr net.Error
+	return errors.As(err, &netErr) || pgconn.Timeout(err) || pgconn.SafeToRetry(err) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// nonNilStrings replaces nil with empty slice, as nil is stored as NULL which array columns don't allow
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nonNilFloats(values []float64) []float64 {
+	if values == nil {
+		return []float64{}
+	}
+	return values
+}
@@ -0,0 +1,88 @@
+package port
+
+// FieldChange tells how a field differs between two versions of a port.
+type FieldChange int
+
+const (
+	// FieldAdded is set only in the new version of the port
+	FieldAdded FieldChange = iota + 1
+	// FieldRemoved is set only in the old version of the port
+	FieldRemoved
+	// FieldChanged is set in both versions of the port, but to different values
+	FieldChanged
+)
+
+// FieldDiff is a difference of a single field of a port. Old and New are values of the field, which are
+// string, []string or *GeoPoint, they're nil when the field isn't set in the version.
+type FieldDiff struct {
+	// Field is named the same way as in port's API representation, i.e. "timezone" or "alias"
+	Field  string
+	Change FieldChange
+	Old    any
+	New    any
+}
+
+// Diff returns differences of all fields, except ID, between old and new versions of a port, in order of
+// the fields of Port. Empty strings and lists are treated the same way as fields which aren't set.
+func Diff(old, new *Port) []FieldDiff {
+	var diffs []FieldDiff
+	addString := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			diffs = append(diffs, fieldDiff(field, oldValue != "", newValue != "", oldValue, newValue))
+		}
+	}
+	addList := func(field string, oldValue, newValue []string) {
+		if !equalLists(oldValue, newValue) {
+			diffs = append(diffs, fieldDiff(field, len(oldValue) > 0, len(newValue) > 0, oldValue, newValue))
+		}
+	}
+
+	addString("name", old.Name, new.Name)
+	addString("city", old.City, new.City)
+	addString("country", old.Country, new.Country)
+	addList("alias", old.Alias, new.Alias)
+	addList("regions", old.Regions, new.Regions)
+	if !equalLocations(old.Location, new.Location) {
+		diffs = append(diffs, fieldDiff("location", old.Location != nil, new.Location != nil, old.Location, new.Location))
+	}
+	addString("province", old.Province, new.Province)
+	addString("timezone", old.Timezone, new.Timezone)
+	addList("unlocs", old.Unlocs, new.Unlocs)
+	addString("code", old.Code, new.Code)
+	return diffs
+}
+
+// fieldDiff creates a diff of a field which values are known to differ.
+func fieldDiff[T any](field string, oldSet, newSet bool, oldValue, newValue T) FieldDiff {
+	diff := FieldDiff{Field: field, Change: FieldChanged}
+	if oldSet {
+		diff.Old = oldValue
+	} else {
+		diff.Change = FieldAdded
+	}
+	if newSet {
+		diff.New = newValue
+	} else {
+		diff.Change = FieldRemoved
+	}
+	return diff
+}
+
+func equalLists(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalLocations(a, b *GeoPoint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,83 @@
+package port
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	stored := &Port{
+		ID:       "AEAJM",
+		Name:     "Ajman",
+		City:     "Ajman",
+		Country:  "United Arab Emirates",
+		Alias:    []string{"alias"},
+		Regions:  []string{"region"},
+		Location: &GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+		Timezone: "Asia/Dubai",
+		Unlocs:   []string{"AEAJM"},
+		Code:     "52000",
+	}
+
+	tests := map[string]struct {
+		update   func(old, updated *Port)
+		expected []FieldDiff
+	}{
+		"should find no differences of equal ports": {
+			update: func(old, updated *Port) {
+				updated.Alias = append([]string{}, updated.Alias...)
+				updated.Location = &GeoPoint{Lat: 25.4052165, Lon: 55.5136433}
+			},
+		},
+		"should treat empty list as not set": {
+			update: func(old, updated *Port) {
+				old.Alias = nil
+				updated.Alias = []string{}
+			},
+		},
+		"should report added fields": {
+			update: func(old, updated *Port) {
+				updated.Province = "Ajman"
+			},
+			expected: []FieldDiff{{Field: "province", Change: FieldAdded, New: "Ajman"}},
+		},
+		"should report removed fields": {
+			update: func(old, updated *Port) {
+				updated.Regions = nil
+				updated.Location = nil
+			},
+			expected: []FieldDiff{
+				{Field: "regions", Change: FieldRemoved, Old: []string{"region"}},
+				{Field: "location", Change: FieldRemoved, Old: &GeoPoint{Lat: 25.4052165, Lon: 55.5136433}},
+			},
+		},
+		"should report changed fields in order": {
+			update: func(old, updated *Port) {
+				updated.Code = "52001"
+				updated.Name = "Ajman Port"
+				updated.Unlocs = []string{"AEAJM", "AEAJX"}
+			},
+			expected: []FieldDiff{
+				{Field: "name", Change: FieldChanged, Old: "Ajman", New: "Ajman Port"},
+				{Field: "unlocs", Change: FieldChanged, Old: []string{"AEAJM"}, New: []string{"AEAJM", "AEAJX"}},
+				{Field: "code", Change: FieldChanged, Old: "52000", New: "52001"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			old := *stored
+			updated := *stored
+			tc.update(&old, &updated)
+
+			// when
+			diffs := Diff(&old, &updated)
+
+			// then
+			assert.Equal(t, tc.expected, diffs)
+		})
+	}
+}
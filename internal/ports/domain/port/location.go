@@ -0,0 +1,63 @@
+package port
+
+// GeoPoint is a location given by latitude and longitude in degrees.
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// NewGeoPoint creates a point after checking that latitude is within [-90, 90] and longitude
+// within [-180, 180].
+func NewGeoPoint(lat, lon float64) (*GeoPoint, error) {
+	var v violations
+	v.checkLatitude("lat", lat)
+	v.checkLongitude("lon", lon)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &GeoPoint{Lat: lat, Lon: lon}, nil
+}
+
+// GeoPointFromCoordinates reads a point from legacy [longitude, latitude] coordinates, the order used
+// by the source data of ports. It returns nil when there are no coordinates.
+func GeoPointFromCoordinates(coordinates []float64) (*GeoPoint, error) {
+	var v violations
+	switch len(coordinates) {
+	case 0:
+		return nil, nil
+	case 2:
+		v.checkLongitude("coordinates[0]", coordinates[0])
+		v.checkLatitude("coordinates[1]", coordinates[1])
+	default:
+		v.add("coordinates", "must be a [longitude, latitude] pair")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &GeoPoint{Lat: coordinates[1], Lon: coordinates[0]}, nil
+}
+
+// ResolveLocation picks port location out of location and legacy coordinates, which are used only
+// when location isn't set. When both are set, they must point at the same place. Location itself
+// is validated along with the rest of the port.
+func ResolveLocation(location *GeoPoint, coordinates []float64) (*GeoPoint, error) {
+	legacyLocation, err := GeoPointFromCoordinates(coordinates)
+	if location == nil || err != nil {
+		return legacyLocation, err
+	}
+	if legacyLocation != nil && *legacyLocation != *location {
+		var v violations
+		v.add("coordinates", "must point at the same place as location")
+		return nil, v.err()
+	}
+	return location, nil
+}
+
+// Coordinates returns the point as legacy [longitude, latitude] coordinates, nil point gives
+// no coordinates.
+func (g *GeoPoint) Coordinates() []float64 {
+	if g == nil {
+		return nil
+	}
+	return []float64{g.Lon, g.Lat}
+}
@@ -0,0 +1,114 @@
+package port
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoPointFromCoordinates(t *testing.T) {
+	tests := map[string]struct {
+		coordinates []float64
+		expected    *GeoPoint
+		err         bool
+	}{
+		"should read longitude followed by latitude": {
+			coordinates: []float64{55.5136433, 25.4052165},
+			expected:    &GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+		},
+		"should return no point for empty coordinates": {
+			coordinates: []float64{},
+			expected:    nil,
+		},
+		"shouldn't accept single coordinate": {
+			coordinates: []float64{90.0},
+			err:         true,
+		},
+		"shouldn't accept more than two coordinates": {
+			coordinates: []float64{55.5, 25.4, 10},
+			err:         true,
+		},
+		"shouldn't accept latitude out of range": {
+			coordinates: []float64{25.4, 155.5},
+			err:         true,
+		},
+		"shouldn't accept longitude out of range": {
+			coordinates: []float64{190, 25.4},
+			err:         true,
+		},
+		"shouldn't accept NaN": {
+			coordinates: []float64{math.NaN(), 25.4},
+			err:         true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			point, err := GeoPointFromCoordinates(tc.coordinates)
+
+			// then
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, point)
+			if point != nil {
+				assert.Equal(t, tc.coordinates, point.Coordinates())
+			}
+		})
+	}
+}
+
+func TestResolvingLocation(t *testing.T) {
+	tests := map[string]struct {
+		location    *GeoPoint
+		coordinates []float64
+		expected    *GeoPoint
+		violation   string
+	}{
+		"should prefer location": {
+			location: &GeoPoint{Lat: 25.4, Lon: 55.5},
+			expected: &GeoPoint{Lat: 25.4, Lon: 55.5},
+		},
+		"should fall back to legacy coordinates": {
+			coordinates: []float64{55.5, 25.4},
+			expected:    &GeoPoint{Lat: 25.4, Lon: 55.5},
+		},
+		"should accept coordinates matching location": {
+			location:    &GeoPoint{Lat: 25.4, Lon: 55.5},
+			coordinates: []float64{55.5, 25.4},
+			expected:    &GeoPoint{Lat: 25.4, Lon: 55.5},
+		},
+		"shouldn't accept coordinates not matching location": {
+			location:    &GeoPoint{Lat: 25.4, Lon: 55.5},
+			coordinates: []float64{25.4, 55.5},
+			violation:   "coordinates",
+		},
+		"shouldn't accept invalid coordinates": {
+			coordinates: []float64{55.5, 95},
+			violation:   "coordinates[1]",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			location, err := ResolveLocation(tc.location, tc.coordinates)
+
+			// then
+			if tc.violation != "" {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				require.Len(t, validationErr.Violations, 1)
+				assert.Equal(t, tc.violation, validationErr.Violations[0].Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, location)
+		})
+	}
+}
@@ -1,50 +1,115 @@
 package port
 
-import "errors"
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound is returned when there is no port with requested ID.
+	ErrNotFound = errors.New("port not found")
+	// ErrAlreadyExists is returned when creating a port with ID which is already taken.
+	ErrAlreadyExists = errors.New("port already exists")
+	// ErrInvalid is matched by errors of invalid ports and queries, i.e. ValidationError or
+	// ErrInvalidPageToken.
+	ErrInvalid = errors.New("invalid argument")
+	// ErrUnavailable is matched by errors of repositories which can't reach their storage at the moment,
+	// so the operation may succeed when it's retried.
+	ErrUnavailable = errors.New("repository unavailable")
+)
+
+// invalidError is an error of invalid argument, it matches ErrInvalid.
+type invalidError string
+
+func (e invalidError) Error() string {
+	return string(e)
+}
+
+func (e invalidError) Is(target error) bool {
+	return target == ErrInvalid
+}
 
 type Port struct {
-	ID          string
-	Name        string
-	City        string
-	Country     string
-	Alias       []string
-	Regions     []string
-	Coordinates []float64
-	Province    string
-	Timezone    string
-	Unlocs      []string
-	Code        string
+	ID      string
+	Name    string
+	City    string
+	Country string
+	Alias   []string
+	Regions []string
+	// Location is nil when it's unknown
+	Location *GeoPoint
+	Province string
+	Timezone string
+	Unlocs   []string
+	Code     string
 }
 
+// NewPort creates a port, returning ValidationError when any of the fields is invalid.
 func NewPort(id string,
 	name string,
 	city string,
 	country string,
 	alias []string,
 	regions []string,
-	coordinates []float64,
+	location *GeoPoint,
 	province string,
 	timezone string,
 	unlocs []string,
 	code string,
 ) (*Port, error) {
-	if id == "" {
-		return nil, errors.New("port id can't be empty")
+	port := &Port{
+		ID:       id,
+		Name:     name,
+		City:     city,
+		Country:  country,
+		Alias:    alias,
+		Regions:  regions,
+		Location: location,
+		Province: province,
+		Timezone: timezone,
+		Unlocs:   unlocs,
+		Code:     code,
 	}
-	if code == "" {
-		return nil, errors.New("port code can't be empty")
+	if err := port.Validate(); err != nil {
+		return nil, err
 	}
-	return &Port{
-		ID:          id,
-		Name:        name,
-		City:        city,
-		Country:     country,
-		Alias:       alias,
-		Regions:     regions,
-		Coordinates: coordinates,
-		Province:    province,
-		Timezone:    timezone,
-		Unlocs:      unlocs,
-		Code:        code,
-	}, nil
+	return port, nil
+}
+
+// Patch returns a copy of the port with given fields replaced by the values from patch.
+// Fields are named the same way as in port's API representation, i.e. "timezone" or "alias".
+// ID of the port can't be patched.
+func (p *Port) Patch(patch *Port, fields []string) (*Port, error) {
+	patched := *p
+	for _, field := range fields {
+		switch field {
+		case "name":
+			patched.Name = patch.Name
+		case "city":
+			patched.City = patch.City
+		case "country":
+			patched.Country = patch.Country
+		case "alias":
+			patched.Alias = patch.Alias
+		case "regions":
+			patched.Regions = patch.Regions
+		// coordinates are legacy representation of location
+		case "location", "coordinates":
+			patched.Location = patch.Location
+		case "province":
+			patched.Province = patch.Province
+		case "timezone":
+			patched.Timezone = patch.Timezone
+		case "unlocs":
+			patched.Unlocs = patch.Unlocs
+		case "code":
+			patched.Code = patch.Code
+		case "id":
+			return nil, violations{{Field: field, Description: "can't be patched"}}.err()
+		default:
+			return nil, violations{{Field: field, Description: "unknown port field"}}.err()
+		}
+	}
+
+	return NewPort(patched.ID, patched.Name, patched.City, patched.Country, patched.Alias, patched.Regions,
+		patched.Location, patched.Province, patched.Timezone, patched.Unlocs, patched.Code)
 }
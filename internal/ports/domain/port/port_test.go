@@ -9,22 +9,41 @@ import (
 
 func TestPortCreation(t *testing.T) {
 	tests := map[string]struct {
-		ID   string
-		code string
-		err  bool
+		ID       string
+		code     string
+		location *GeoPoint
+		err      bool
 	}{
 		"should create port when ID and code fields are present": {
-			ID:   "some-id",
+			ID:   "GBLON",
 			code: "some-code",
 			err:  false,
 		},
+		"should create port with location": {
+			ID:       "GBLON",
+			code:     "some-code",
+			location: &GeoPoint{Lat: 51.5072, Lon: -0.1275},
+			err:      false,
+		},
+		"shouldn't create port with latitude out of range": {
+			ID:       "GBLON",
+			code:     "some-code",
+			location: &GeoPoint{Lat: 90.5, Lon: -0.1275},
+			err:      true,
+		},
+		"shouldn't create port with longitude out of range": {
+			ID:       "GBLON",
+			code:     "some-code",
+			location: &GeoPoint{Lat: 51.5072, Lon: -180.5},
+			err:      true,
+		},
 		"shouldn't create port when ID is missing": {
 			ID:   "",
 			code: "some-code",
 			err:  true,
 		},
 		"shouldn't create port when code is missing": {
-			ID:   "some-id",
+			ID:   "GBLON",
 			code: "",
 			err:  true,
 		},
@@ -38,31 +57,124 @@ func TestPortCreation(t *testing.T) {
 			country := "United Kingdom"
 			alias := []string{"alias"}
 			regions := []string{"region"}
-			coordinates := []float64{90.0, 90.0}
 			province := "province"
 			timezone := "UTC"
-			unlocs := []string{"abc"}
+			unlocs := []string{"GBLON"}
 
 			// when
-			port, err := NewPort(tc.ID, name, city, country, alias, regions, coordinates, province, timezone, unlocs, tc.code)
+			port, err := NewPort(tc.ID, name, city, country, alias, regions, tc.location, province, timezone, unlocs, tc.code)
 			if tc.err {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, Port{
-					ID:          tc.ID,
-					Name:        name,
-					City:        city,
-					Country:     country,
-					Alias:       alias,
-					Regions:     regions,
-					Coordinates: coordinates,
-					Province:    province,
-					Timezone:    timezone,
-					Unlocs:      unlocs,
-					Code:        tc.code,
+					ID:       tc.ID,
+					Name:     name,
+					City:     city,
+					Country:  country,
+					Alias:    alias,
+					Regions:  regions,
+					Location: tc.location,
+					Province: province,
+					Timezone: timezone,
+					Unlocs:   unlocs,
+					Code:     tc.code,
 				}, *port)
 			}
 		})
 	}
 }
+
+func TestPortPatching(t *testing.T) {
+	tests := map[string]struct {
+		fields   []string
+		expected Port
+		err      bool
+		// patchedLocation replaces location of the patch when set
+		patchedLocation *GeoPoint
+	}{
+		"should replace only listed fields": {
+			fields: []string{"timezone", "alias"},
+			expected: Port{
+				ID:       "AEAJM",
+				Name:     "port-name",
+				Location: &GeoPoint{Lat: 25.4, Lon: 55.5},
+				Timezone: "Asia/Dubai",
+				Alias:    []string{"new-alias"},
+				Code:     "some-code",
+			},
+		},
+		"should clear field which is empty in patch": {
+			fields: []string{"name"},
+			expected: Port{
+				ID:       "AEAJM",
+				Location: &GeoPoint{Lat: 25.4, Lon: 55.5},
+				Timezone: "UTC",
+				Alias:    []string{"alias"},
+				Code:     "some-code",
+			},
+		},
+		"shouldn't patch port id": {
+			fields: []string{"id"},
+			err:    true,
+		},
+		"shouldn't patch unknown field": {
+			fields: []string{"unknown"},
+			err:    true,
+		},
+		"should patch location using legacy coordinates field": {
+			fields: []string{"coordinates"},
+			expected: Port{
+				ID:       "AEAJM",
+				Name:     "port-name",
+				Location: &GeoPoint{Lat: 54.35, Lon: 18.65},
+				Timezone: "UTC",
+				Alias:    []string{"alias"},
+				Code:     "some-code",
+			},
+		},
+		"shouldn't patch port into invalid one": {
+			fields: []string{"code"},
+			err:    true,
+		},
+		"shouldn't patch location out of range": {
+			fields:          []string{"location"},
+			err:             true,
+			patchedLocation: &GeoPoint{Lat: 100, Lon: 18.65},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			port := &Port{
+				ID:       "AEAJM",
+				Name:     "port-name",
+				Location: &GeoPoint{Lat: 25.4, Lon: 55.5},
+				Timezone: "UTC",
+				Alias:    []string{"alias"},
+				Code:     "some-code",
+			}
+			patch := &Port{
+				ID:       "AEAUH",
+				Location: &GeoPoint{Lat: 54.35, Lon: 18.65},
+				Timezone: "Asia/Dubai",
+				Alias:    []string{"new-alias"},
+			}
+			if tc.patchedLocation != nil {
+				patch.Location = tc.patchedLocation
+			}
+
+			// when
+			patched, err := port.Patch(patch, tc.fields)
+
+			// then
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, *patched)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package port
+
+import "strings"
+
+// ErrInvalidPageToken is returned when page token wasn't issued by the repository
+// for the same ordering of ports.
+var ErrInvalidPageToken error = invalidError("invalid page token")
+
+// OrderField is a field by which ports are ordered.
+type OrderField int
+
+const (
+	OrderByID OrderField = iota
+	OrderByName
+	OrderByCountry
+	// OrderByInsertionTime orders ports by the time they were created in the repository,
+	// updates of the port don't change its position
+	OrderByInsertionTime
+)
+
+// Query describes which ports should be fetched from the repository.
+type Query struct {
+	// OrderBy selects the order of ports. Ports with equal values of the field are ordered
+	// by their ID, so the order is always deterministic.
+	OrderBy    OrderField
+	Descending bool
+	// PageSize limits number of returned ports, all ports are returned when it's not positive
+	PageSize int
+	// PageToken is the token returned with previous page, empty for the first page
+	PageToken string
+	// Filter limits returned ports to the matching ones
+	Filter Filter
+}
+
+// Filter selects ports by their fields. Empty fields match every port, set fields must all match.
+// Values are compared ignoring case.
+type Filter struct {
+	Country  string
+	Province string
+	City     string
+	Timezone string
+	Code     string
+	// Region matches ports having it among their regions
+	Region string
+	// Unloc matches ports having it among their unlocs
+	Unloc string
+}
+
+// Matches reports whether the port satisfies all conditions of the filter.
+func (f Filter) Matches(port *Port) bool {
+	return matches(f.Country, port.Country) &&
+		matches(f.Province, port.Province) &&
+		matches(f.City, port.City) &&
+		matches(f.Timezone, port.Timezone) &&
+		matches(f.Code, port.Code) &&
+		contains(f.Region, port.Regions) &&
+		contains(f.Unloc, port.Unlocs)
+}
+
+func matches(expected, value string) bool {
+	return expected == "" || strings.EqualFold(expected, value)
+}
+
+func contains(expected string, values []string) bool {
+	if expected == "" {
+		return true
+	}
+	for _, value := range values {
+		if strings.EqualFold(expected, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package port
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMatching(t *testing.T) {
+	port := &Port{
+		ID:       "AEAJM",
+		City:     "Ajman",
+		Country:  "United Arab Emirates",
+		Regions:  []string{"Middle East"},
+		Province: "Ajman",
+		Timezone: "Asia/Dubai",
+		Unlocs:   []string{"AEAJM", "AEAJX"},
+		Code:     "52000",
+	}
+
+	tests := map[string]struct {
+		filter  Filter
+		matches bool
+	}{
+		"should match every port with empty filter": {
+			filter:  Filter{},
+			matches: true,
+		},
+		"should match when all set fields are equal": {
+			filter:  Filter{Country: "United Arab Emirates", Province: "Ajman", City: "Ajman", Timezone: "Asia/Dubai", Code: "52000"},
+			matches: true,
+		},
+		"should match ignoring case": {
+			filter:  Filter{Country: "united arab emirates", Timezone: "ASIA/DUBAI"},
+			matches: true,
+		},
+		"should match any of port regions and unlocs": {
+			filter:  Filter{Region: "middle east", Unloc: "AEAJX"},
+			matches: true,
+		},
+		"shouldn't match when any of set fields is different": {
+			filter:  Filter{Country: "United Arab Emirates", Code: "52001"},
+			matches: false,
+		},
+		"shouldn't match partial value": {
+			filter:  Filter{Country: "United"},
+			matches: false,
+		},
+		"shouldn't match region which port doesn't have": {
+			filter:  Filter{Region: "Europe"},
+			matches: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			matches := tc.filter.Matches(port)
+
+			// then
+			assert.Equal(t, tc.matches, matches)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package port
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	// time zones are validated against embedded database, as the service runs in images without one
+	_ "time/tzdata"
+
+	"github.com/arturskrzydlo/ports/internal/common/countries"
+)
+
+// unlocodePattern matches UN/LOCODE, which is ISO 3166-1 alpha-2 country code followed by three letters
+// or digits identifying location within the country. Digits 0 and 1 aren't used to avoid confusion
+// with letters O and I.
+var unlocodePattern = regexp.MustCompile(`^[A-Z]{2}[A-Z2-9]{3}$`)
+
+// FieldViolation describes why a single field of a port is invalid. Field is named the same way as
+// in port's API representation, i.e. "unlocs[1]" or "location.lat".
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError is returned when port is invalid, it lists all violations found in the port.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		descriptions[i] = violation.Field + ": " + violation.Description
+	}
+	return "invalid port: " + strings.Join(descriptions, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// JoinValidationErrors merges violations of all validation errors into one. When some of the errors
+// aren't validation errors, they are joined with errors.Join instead.
+func JoinValidationErrors(errs ...error) error {
+	var violations violations
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return errors.Join(errs...)
+		}
+		violations = append(violations, validationErr.Violations...)
+	}
+	return violations.err()
+}
+
+// Validate checks all fields of the port and returns ValidationError listing every violation found.
+func (p *Port) Validate() error {
+	var v violations
+	switch {
+	case p.ID == "":
+		v.add("id", "can't be empty")
+	case !unlocodePattern.MatchString(p.ID):
+		v.add("id", "must be a UN/LOCODE, i.e. AEAJM")
+	}
+	if p.Code == "" {
+		v.add("code", "can't be empty")
+	}
+	if p.Country != "" && !countries.IsCountry(p.Country) {
+		v.add("country", "must be ISO 3166-1 alpha-2 code or name of a country")
+	}
+	if p.Timezone != "" && !isTimezone(p.Timezone) {
+		v.add("timezone", "must be IANA time zone name, i.e. Asia/Dubai")
+	}
+	for i, unloc := range p.Unlocs {
+		if !unlocodePattern.MatchString(unloc) {
+			v.add(fmt.Sprintf("unlocs[%d]", i), "must be a UN/LOCODE, i.e. AEAJM")
+		}
+	}
+	for i, alias := range p.Alias {
+		for j := 0; j < i; j++ {
+			if strings.EqualFold(alias, p.Alias[j]) {
+				v.add(fmt.Sprintf("alias[%d]", i), fmt.Sprintf("duplicates alias[%d]", j))
+				break
+			}
+		}
+	}
+	if p.Location != nil {
+		v.checkLatitude("location.lat", p.Location.Lat)
+		v.checkLongitude("location.lon", p.Location.Lon)
+	}
+	return v.err()
+}
+
+func isTimezone(name string) bool {
+	// Local is the time zone of the machine, not a name of IANA time zone
+	if name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// violations collects violations found while validating a port.
+type violations []FieldViolation
+
+func (v *violations) add(field, description string) {
+	*v = append(*v, FieldViolation{Field: field, Description: description})
+}
+
+func (v *violations) checkLatitude(field string, lat float64) {
+	// comparisons are negated, so NaN values are rejected too
+	if !(lat >= -90 && lat <= 90) {
+		v.add(field, fmt.Sprintf("latitude must be between -90 and 90, got %v", lat))
+	}
+}
+
+func (v *violations) checkLongitude(field string, lon float64) {
+	if !(lon >= -180 && lon <= 180) {
+		v.add(field, fmt.Sprintf("longitude must be between -180 and 180, got %v", lon))
+	}
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
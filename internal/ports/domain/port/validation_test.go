@@ -0,0 +1,130 @@
+package port
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortValidation(t *testing.T) {
+	tests := map[string]struct {
+		change     func(port *Port)
+		violations []string
+	}{
+		"should accept valid port": {
+			change: func(port *Port) {},
+		},
+		"should accept country code and name in any case": {
+			change: func(port *Port) { port.Country = "united arab emirates" },
+		},
+		"should accept common country name": {
+			change: func(port *Port) { port.Country = "United Kingdom" },
+		},
+		"should accept port without optional fields": {
+			change: func(port *Port) {
+				port.Country = ""
+				port.Timezone = ""
+				port.Location = nil
+			},
+		},
+		"shouldn't accept ID which isn't UN/LOCODE": {
+			change:     func(port *Port) { port.ID = "aeajm" },
+			violations: []string{"id"},
+		},
+		"shouldn't accept UN/LOCODE with digit one": {
+			change:     func(port *Port) { port.ID = "AEAJ1" },
+			violations: []string{"id"},
+		},
+		"shouldn't accept unknown country": {
+			change:     func(port *Port) { port.Country = "Atlantis" },
+			violations: []string{"country"},
+		},
+		"shouldn't accept unknown time zone": {
+			change:     func(port *Port) { port.Timezone = "Asia/Atlantis" },
+			violations: []string{"timezone"},
+		},
+		"shouldn't accept local time zone": {
+			change:     func(port *Port) { port.Timezone = "Local" },
+			violations: []string{"timezone"},
+		},
+		"shouldn't accept duplicated aliases ignoring case": {
+			change:     func(port *Port) { port.Alias = []string{"Ajman", "Ujman", "AJMAN"} },
+			violations: []string{"alias[2]"},
+		},
+		"should report all violations at once": {
+			change: func(port *Port) {
+				port.ID = ""
+				port.Code = ""
+				port.Unlocs = []string{"AEAJM", "AE AJM"}
+				port.Location = &GeoPoint{Lat: 91, Lon: 181}
+			},
+			violations: []string{"id", "code", "unlocs[1]", "location.lat", "location.lon"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			port := &Port{
+				ID:       "AEAJM",
+				Name:     "Ajman",
+				Country:  "AE",
+				Alias:    []string{"Ajman"},
+				Location: &GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+				Timezone: "Asia/Dubai",
+				Unlocs:   []string{"AEAJM"},
+				Code:     "52000",
+			}
+			tc.change(port)
+
+			// when
+			err := port.Validate()
+
+			// then
+			if len(tc.violations) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			fields := make([]string, len(validationErr.Violations))
+			for i, violation := range validationErr.Violations {
+				fields[i] = violation.Field
+			}
+			assert.Equal(t, tc.violations, fields)
+		})
+	}
+}
+
+func TestJoiningValidationErrors(t *testing.T) {
+	t.Run("should merge violations", func(t *testing.T) {
+		// when
+		err := JoinValidationErrors(
+			&ValidationError{Violations: []FieldViolation{{Field: "id"}}},
+			nil,
+			&ValidationError{Violations: []FieldViolation{{Field: "code"}}},
+		)
+
+		// then
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []FieldViolation{{Field: "id"}, {Field: "code"}}, validationErr.Violations)
+	})
+
+	t.Run("should keep other errors", func(t *testing.T) {
+		// given
+		otherErr := errors.New("other")
+
+		// when
+		err := JoinValidationErrors(&ValidationError{Violations: []FieldViolation{{Field: "id"}}}, otherErr)
+
+		// then
+		assert.ErrorIs(t, err, otherErr)
+	})
+
+	t.Run("should return nil without errors", func(t *testing.T) {
+		assert.NoError(t, JoinValidationErrors(nil, nil))
+	})
+}
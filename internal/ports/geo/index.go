@@ -0,0 +1,190 @@
+// Package geo provides in-memory spatial index of ports used for nearest-port and bounding-box
+// queries.
+package geo
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"sync"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0088
+
+// NearbyPort is a port along with its great-circle distance from the queried point.
+type NearbyPort struct {
+	Port       *domainPort.Port
+	DistanceKm float64
+}
+
+// Index finds ports by their location, ports without location aren't indexed.
+// It's safe for concurrent use.
+type Index struct {
+	mutex sync.RWMutex
+	ports map[string]*entry
+
+	// structuresMutex guards search structures, which are built lazily by readers holding
+	// read lock and dropped on every write
+	structuresMutex sync.Mutex
+	tree            *node
+	// byLat holds ports sorted by latitude, so ports within latitude range are next to each other
+	byLat []*entry
+}
+
+type entry struct {
+	port     *domainPort.Port
+	lat, lon float64
+	// point is a position of the port on the unit sphere, straight-line distance between points
+	// grows together with the great-circle distance
+	point [3]float64
+}
+
+func NewIndex() *Index {
+	return &Index{ports: make(map[string]*entry)}
+}
+
+// Add indexes the port, replacing previously indexed port with the same ID. Port without
+// location is only removed from the index.
+func (i *Index) Add(port *domainPort.Port) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.dropStructures()
+
+	if port.Location == nil {
+		delete(i.ports, port.ID)
+		return
+	}
+	lat, lon := port.Location.Lat, port.Location.Lon
+	i.ports[port.ID] = &entry{port: port, lat: lat, lon: lon, point: toPoint(lat, lon)}
+}
+
+// Remove removes the port from the index, it's a no-op when port isn't indexed.
+func (i *Index) Remove(id string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if _, ok := i.ports[id]; ok {
+		delete(i.ports, id)
+		i.dropStructures()
+	}
+}
+
+// Nearest returns at most k ports closest to the point, the closest first. Ports farther than
+// maxDistanceKm are skipped, unless it's not positive.
+func (i *Index) Nearest(lat, lon float64, k int, maxDistanceKm float64) []NearbyPort {
+	if k <= 0 {
+		return []NearbyPort{}
+	}
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	maxChord := math.Inf(1)
+	if maxDistanceKm > 0 {
+		maxChord = chordLength(maxDistanceKm)
+	}
+	search := &nearestSearch{target: toPoint(lat, lon), k: k, maxChord: maxChord}
+	search.visit(i.searchTree())
+
+	nearest := make([]NearbyPort, len(search.found))
+	for pos := len(search.found) - 1; pos >= 0; pos-- {
+		found := heap.Pop(&search.found).(candidate)
+		nearest[pos] = NearbyPort{Port: found.entry.port, DistanceKm: arcLength(found.chord)}
+	}
+	return nearest
+}
+
+// WithinBoundingBox returns at most limit ports located within the box, ordered by ID. Box crosses
+// the antimeridian when minLon is greater than maxLon.
+func (i *Index) WithinBoundingBox(minLat, minLon, maxLat, maxLon float64, limit int) []*domainPort.Port {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	byLat := i.sortedByLat()
+	start := sort.Search(len(byLat), func(pos int) bool { return byLat[pos].lat >= minLat })
+	ports := make([]*domainPort.Port, 0)
+	for _, e := range byLat[start:] {
+		if e.lat > maxLat {
+			break
+		}
+		if withinLongitudes(e.lon, minLon, maxLon) {
+			ports = append(ports, e.port)
+		}
+	}
+
+	sort.Slice(ports, func(a, b int) bool { return ports[a].ID < ports[b].ID })
+	if limit > 0 && len(ports) > limit {
+		ports = ports[:limit]
+	}
+	return ports
+}
+
+// dropStructures must be called with write lock held.
+func (i *Index) dropStructures() {
+	i.structuresMutex.Lock()
+	defer i.structuresMutex.Unlock()
+	i.tree = nil
+	i.byLat = nil
+}
+
+// searchTree returns k-d tree of all indexed ports, it must be called with read lock held.
+func (i *Index) searchTree() *node {
+	i.structuresMutex.Lock()
+	defer i.structuresMutex.Unlock()
+	if i.tree == nil && len(i.ports) > 0 {
+		i.tree = build(i.entries(), 0)
+	}
+	return i.tree
+}
+
+// sortedByLat must be called with read lock held.
+func (i *Index) sortedByLat() []*entry {
+	i.structuresMutex.Lock()
+	defer i.structuresMutex.Unlock()
+	if i.byLat == nil {
+		i.byLat = i.entries()
+		sort.Slice(i.byLat, func(a, b int) bool { return i.byLat[a].lat < i.byLat[b].lat })
+	}
+	return i.byLat
+}
+
+func (i *Index) entries() []*entry {
+	entries := make([]*entry, 0, len(i.ports))
+	for _, e := range i.ports {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func withinLongitudes(lon, minLon, maxLon float64) bool {
+	if minLon <= maxLon {
+		return lon >= minLon && lon <= maxLon
+	}
+	return lon >= minLon || lon <= maxLon
+}
+
+func toPoint(lat, lon float64) [3]float64 {
+	latRad, lonRad := lat*math.Pi/180, lon*math.Pi/180
+	return [3]float64{
+		math.Cos(latRad) * math.Cos(lonRad),
+		math.Cos(latRad) * math.Sin(lonRad),
+		math.Sin(latRad),
+	}
+}
+
+// chordLength converts great-circle distance to straight-line distance on the unit sphere.
+func chordLength(distanceKm float64) float64 {
+	angle := math.Min(distanceKm/earthRadiusKm, math.Pi)
+	return 2 * math.Sin(angle/2)
+}
+
+// arcLength converts straight-line distance on the unit sphere to great-circle distance.
+func arcLength(chord float64) float64 {
+	return 2 * math.Asin(math.Min(chord/2, 1)) * earthRadiusKm
+}
+
+func chord(a, b [3]float64) float64 {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
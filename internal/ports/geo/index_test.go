@@ -0,0 +1,179 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+func TestFindingNearestPorts(t *testing.T) {
+	index := newTestIndex()
+
+	t.Run("should return closest ports ordered by distance", func(t *testing.T) {
+		// when
+		nearest := index.Nearest(25.25, 55.27, 3, 0)
+
+		// then
+		require.Len(t, nearest, 3)
+		assert.Equal(t, []string{"AEDXB", "AEAJM", "AEAUH"}, nearbyIDs(nearest))
+		assert.InDelta(t, 0, nearest[0].DistanceKm, 0.001)
+		assert.InDelta(t, haversine(25.25, 55.27, nearest[2].Port), nearest[2].DistanceKm, 0.001)
+	})
+
+	t.Run("should skip ports farther than max distance", func(t *testing.T) {
+		// when
+		nearest := index.Nearest(25.25, 55.27, 10, 50)
+
+		// then
+		assert.Equal(t, []string{"AEDXB", "AEAJM"}, nearbyIDs(nearest))
+	})
+
+	t.Run("should find ports across the antimeridian", func(t *testing.T) {
+		// when
+		nearest := index.Nearest(-16, 179.9, 2, 0)
+
+		// then
+		assert.Equal(t, []string{"FJSUV", "WSAPW"}, nearbyIDs(nearest))
+	})
+
+	t.Run("should return no ports for non-positive k", func(t *testing.T) {
+		assert.Empty(t, index.Nearest(0, 0, 0, 0))
+		assert.Empty(t, NewIndex().Nearest(0, 0, 1, 0))
+	})
+
+	t.Run("should find the same ports as exhaustive search", func(t *testing.T) {
+		// given
+		random := rand.New(rand.NewSource(1))
+		randomIndex := NewIndex()
+		ports := make([]*domainPort.Port, 0, 500)
+		for i := 0; i < 500; i++ {
+			port := &domainPort.Port{
+				ID:       fmt.Sprintf("P%03d", i),
+				Location: &domainPort.GeoPoint{Lat: random.Float64()*180 - 90, Lon: random.Float64()*360 - 180},
+			}
+			ports = append(ports, port)
+			randomIndex.Add(port)
+		}
+
+		for i := 0; i < 50; i++ {
+			lat, lon := random.Float64()*180-90, random.Float64()*360-180
+
+			// when
+			nearest := randomIndex.Nearest(lat, lon, 5, 0)
+
+			// then
+			sort.Slice(ports, func(a, b int) bool {
+				return haversine(lat, lon, ports[a]) < haversine(lat, lon, ports[b])
+			})
+			expected := make([]string, 5)
+			for j := range expected {
+				expected[j] = ports[j].ID
+			}
+			assert.Equal(t, expected, nearbyIDs(nearest))
+		}
+	})
+}
+
+func TestFindingPortsInBoundingBox(t *testing.T) {
+	index := newTestIndex()
+
+	t.Run("should return ports within the box ordered by id", func(t *testing.T) {
+		// when
+		ports := index.WithinBoundingBox(24, 54, 26, 56, 0)
+
+		// then
+		assert.Equal(t, []string{"AEAJM", "AEAUH", "AEDXB"}, portIDs(ports))
+	})
+
+	t.Run("should return ports within the box crossing the antimeridian", func(t *testing.T) {
+		// when
+		ports := index.WithinBoundingBox(-20, 170, -10, -170, 0)
+
+		// then
+		assert.Equal(t, []string{"FJSUV", "WSAPW"}, portIDs(ports))
+	})
+
+	t.Run("should return at most limit ports", func(t *testing.T) {
+		// when
+		ports := index.WithinBoundingBox(-90, -180, 90, 180, 2)
+
+		// then
+		assert.Equal(t, []string{"AEAJM", "AEAUH"}, portIDs(ports))
+	})
+}
+
+func TestUpdatingIndex(t *testing.T) {
+	t.Run("shouldn't index port without location", func(t *testing.T) {
+		// given
+		index := NewIndex()
+
+		// when
+		index.Add(&domainPort.Port{ID: "unknown-location"})
+
+		// then
+		assert.Empty(t, index.WithinBoundingBox(-90, -180, 90, 180, 0))
+	})
+
+	t.Run("should move and remove ports", func(t *testing.T) {
+		// given
+		index := newTestIndex()
+		require.Equal(t, "GBLON", nearbyIDs(index.Nearest(51.5, -0.1, 1, 0))[0])
+
+		// when
+		index.Add(&domainPort.Port{ID: "GBLON", Location: &domainPort.GeoPoint{Lat: 54.35, Lon: 18.65}})
+		index.Remove("PLGDN")
+		index.Remove("unknown")
+
+		// then
+		assert.Empty(t, index.Nearest(51.5, -0.1, 1, 100))
+		assert.Equal(t, []string{"GBLON"}, nearbyIDs(index.Nearest(54.35, 18.65, 1, 0)))
+	})
+}
+
+func newTestIndex() *Index {
+	index := NewIndex()
+	for id, location := range map[string]domainPort.GeoPoint{
+		"AEAJM": {Lat: 25.4052165, Lon: 55.5136433},
+		"AEAUH": {Lat: 24.47, Lon: 54.37},
+		"AEDXB": {Lat: 25.25, Lon: 55.27},
+		"GBLON": {Lat: 51.5, Lon: -0.1},
+		"PLGDN": {Lat: 54.35, Lon: 18.65},
+		"FJSUV": {Lat: -18.14, Lon: 178.44},
+		"WSAPW": {Lat: -13.83, Lon: -171.76},
+	} {
+		location := location
+		index.Add(&domainPort.Port{ID: id, Location: &location})
+	}
+	return index
+}
+
+func haversine(lat, lon float64, port *domainPort.Port) float64 {
+	toRad := math.Pi / 180
+	portLat, portLon := port.Location.Lat*toRad, port.Location.Lon*toRad
+	lat, lon = lat*toRad, lon*toRad
+	h := math.Pow(math.Sin((portLat-lat)/2), 2) + math.Cos(lat)*math.Cos(portLat)*math.Pow(math.Sin((portLon-lon)/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func nearbyIDs(nearest []NearbyPort) []string {
+	ids := make([]string, len(nearest))
+	for i, nearby := range nearest {
+		ids[i] = nearby.Port.ID
+	}
+	return ids
+}
+
+func portIDs(ports []*domainPort.Port) []string {
+	ids := make([]string, len(ports))
+	for i, port := range ports {
+		ids[i] = port.ID
+	}
+	return ids
+}
@@ -0,0 +1,117 @@
+package geo
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// node of k-d tree splitting points on the unit sphere by one of three axes.
+type node struct {
+	entry       *entry
+	axis        int
+	left, right *node
+}
+
+// build creates balanced tree by splitting entries at the median of the axis on each level.
+func build(entries []*entry, depth int) *node {
+	if len(entries) == 0 {
+		return nil
+	}
+	axis := depth % 3
+	sort.Slice(entries, func(a, b int) bool { return entries[a].point[axis] < entries[b].point[axis] })
+	median := len(entries) / 2
+	return &node{
+		entry: entries[median],
+		axis:  axis,
+		left:  build(entries[:median], depth+1),
+		right: build(entries[median+1:], depth+1),
+	}
+}
+
+type nearestSearch struct {
+	target   [3]float64
+	k        int
+	maxChord float64
+	found    candidates
+}
+
+// visit searches the subtree, skipping branches which can't contain points closer than
+// the farthest point found so far.
+func (s *nearestSearch) visit(n *node) {
+	if n == nil {
+		return
+	}
+
+	s.offer(candidate{entry: n.entry, chord: chord(s.target, n.entry.point)})
+
+	diff := s.target[n.axis] - n.entry.point[n.axis]
+	near, far := n.left, n.right
+	if diff > 0 {
+		near, far = n.right, n.left
+	}
+	s.visit(near)
+	// distance to the splitting plane is the lower bound of distance to any point behind it
+	if abs(diff) <= s.bound() {
+		s.visit(far)
+	}
+}
+
+func (s *nearestSearch) offer(c candidate) {
+	if c.chord > s.maxChord {
+		return
+	}
+	if len(s.found) < s.k {
+		heap.Push(&s.found, c)
+		return
+	}
+	if c.closerThan(s.found[0]) {
+		s.found[0] = c
+		heap.Fix(&s.found, 0)
+	}
+}
+
+// bound returns the distance within which closer points have to be found.
+func (s *nearestSearch) bound() float64 {
+	if len(s.found) < s.k {
+		return s.maxChord
+	}
+	return s.found[0].chord
+}
+
+type candidate struct {
+	entry *entry
+	chord float64
+}
+
+// closerThan orders candidates by distance and then by port ID, so results are deterministic.
+func (c candidate) closerThan(other candidate) bool {
+	if c.chord != other.chord {
+		return c.chord < other.chord
+	}
+	return c.entry.port.ID < other.entry.port.ID
+}
+
+// candidates is a max-heap keeping the farthest candidate on top.
+type candidates []candidate
+
+func (c candidates) Len() int           { return len(c) }
+func (c candidates) Less(a, b int) bool { return c[b].closerThan(c[a]) }
+func (c candidates) Swap(a, b int)      { c[a], c[b] = c[b], c[a] }
+
+func (c *candidates) Push(x any) {
+	*c = append(*c, x.(candidate))
+}
+
+func (c *candidates) Pop() any {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
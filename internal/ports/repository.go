@@ -7,6 +7,26 @@ import (
 )
 
 type Repository interface {
+	// CreatePort stores a new port or returns port.ErrAlreadyExists if port with the same ID exists.
 	CreatePort(ctx context.Context, port *port.Port) error
-	GetPorts(ctx context.Context) ([]*port.Port, error)
+	// UpsertPort stores the port, replacing any port with the same ID. It reports
+	// whether the port was newly created rather than updated.
+	UpsertPort(ctx context.Context, port *port.Port) (created bool, err error)
+	// UpsertPorts stores all the ports atomically, either all of them are stored or none when error is
+	// returned. It reports for every port whether it was newly created, the same way as UpsertPort
+	// called for the ports in order would.
+	UpsertPorts(ctx context.Context, ports []*port.Port) (created []bool, err error)
+	// ReplacePorts stores all the ports atomically the same way as UpsertPorts and deletes, in the same
+	// transaction, every other port unless its ID is among keepIDs. It returns IDs of deleted ports in
+	// ascending order.
+	ReplacePorts(ctx context.Context, ports []*port.Port, keepIDs []string) (created []bool, deleted []string, err error)
+	// UpdatePort replaces existing port or returns port.ErrNotFound if there is no such port.
+	UpdatePort(ctx context.Context, port *port.Port) error
+	// DeletePort removes port with given ID or returns port.ErrNotFound if there is no such port.
+	DeletePort(ctx context.Context, id string) error
+	// GetPort returns port with given ID or port.ErrNotFound if there is no such port.
+	GetPort(ctx context.Context, id string) (*port.Port, error)
+	// GetPorts returns ports matching the query together with a token of the next page.
+	// The token is empty when there are no more ports to fetch.
+	GetPorts(ctx context.Context, query port.Query) (ports []*port.Port, nextPageToken string, err error)
 }
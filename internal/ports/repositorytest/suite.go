@@ -0,0 +1,637 @@
+// Package repositorytest provides a conformance suite which verifies that an implementation of
+// ports.Repository behaves the same way as all the other ones.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/arturskrzydlo/ports/internal/ports"
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+const concurrentWriters = 20
+
+// Run runs the conformance suite against repositories created by newRepository. It's called
+// before every test and must return an empty repository.
+func Run(t *testing.T, newRepository func(t *testing.T) ports.Repository) {
+	t.Helper()
+	suite.Run(t, &repositorySuite{newRepository: newRepository})
+}
+
+type repositorySuite struct {
+	suite.Suite
+
+	newRepository func(t *testing.T) ports.Repository
+	repo          ports.Repository
+}
+
+func (s *repositorySuite) SetupTest() {
+	s.repo = s.newRepository(s.T())
+}
+
+func (s *repositorySuite) TestCreatingPorts() {
+	ctx := context.Background()
+
+	s.Run("should create new port", func() {
+		// when
+		err := s.repo.CreatePort(ctx, createPort("AEAJM"))
+
+		// then
+		s.Require().NoError(err)
+		s.assertStored(createPort("AEAJM"))
+	})
+
+	s.Run("shouldn't overwrite already existing port", func() {
+		// given
+		updatedPort := createPort("AEAJM")
+		updatedPort.Name = "updated-name"
+
+		// when
+		err := s.repo.CreatePort(ctx, updatedPort)
+
+		// then
+		s.Assert().ErrorIs(err, domainPort.ErrAlreadyExists)
+		s.assertStored(createPort("AEAJM"))
+	})
+}
+
+func (s *repositorySuite) TestUpsertingPorts() {
+	ctx := context.Background()
+
+	s.Run("should report created port", func() {
+		// when
+		created, err := s.repo.UpsertPort(ctx, createPort("AEAJM"))
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().True(created)
+		s.assertStored(createPort("AEAJM"))
+	})
+
+	s.Run("should replace existing port and report it as updated", func() {
+		// given
+		updatedPort := createPort("AEAJM")
+		updatedPort.Name = "updated-name"
+		updatedPort.Alias = []string{}
+
+		// when
+		created, err := s.repo.UpsertPort(ctx, updatedPort)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().False(created)
+		s.assertStored(updatedPort)
+		s.assertStoredIDs("AEAJM")
+	})
+}
+
+func (s *repositorySuite) TestUpsertingPortsInBatch() {
+	ctx := context.Background()
+
+	s.Run("should store all ports and report which of them were created", func() {
+		// given
+		s.Require().NoError(s.repo.CreatePort(ctx, createPort("AEAUH")))
+		updatedPort := createPort("AEAUH")
+		updatedPort.Name = "updated-name"
+		repeatedPort := createPort("AEAJM")
+		repeatedPort.Name = "repeated-name"
+
+		// when
+		created, err := s.repo.UpsertPorts(ctx, []*domainPort.Port{createPort("AEAJM"), updatedPort, repeatedPort})
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal([]bool{true, false, false}, created)
+		s.assertStored(repeatedPort)
+		s.assertStored(updatedPort)
+		s.assertStoredIDs("AEAJM", "AEAUH")
+	})
+
+	s.Run("should store nothing when any of the ports can't be stored", func() {
+		// when
+		_, err := s.repo.UpsertPorts(ctx, []*domainPort.Port{createPort("AEDXB"), nil})
+
+		// then
+		s.Assert().Error(err)
+		s.assertStoredIDs("AEAJM", "AEAUH")
+	})
+
+	s.Run("should accept empty batch", func() {
+		// when
+		created, err := s.repo.UpsertPorts(ctx, nil)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Empty(created)
+	})
+}
+
+func (s *repositorySuite) TestReplacingPorts() {
+	ctx := context.Background()
+
+	s.Run("should store the ports and delete the rest except kept ones", func() {
+		// given
+		for _, id := range []string{"AEAJM", "AEAUH", "AEDXB", "GBLON"} {
+			s.Require().NoError(s.repo.CreatePort(ctx, createPort(id)))
+		}
+		updatedPort := createPort("AEAUH")
+		updatedPort.Name = "updated-name"
+
+		// when
+		created, deleted, err := s.repo.ReplacePorts(ctx,
+			[]*domainPort.Port{updatedPort, createPort("GBSOU")}, []string{"GBLON", "USNYC"})
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal([]bool{false, true}, created)
+		s.Assert().Equal([]string{"AEAJM", "AEDXB"}, deleted)
+		s.assertStored(updatedPort)
+		s.assertStoredIDs("AEAUH", "GBLON", "GBSOU")
+	})
+
+	s.Run("should change nothing when any of the ports can't be stored", func() {
+		// when
+		_, _, err := s.repo.ReplacePorts(ctx, []*domainPort.Port{createPort("AEDXB"), nil}, nil)
+
+		// then
+		s.Assert().Error(err)
+		s.assertStoredIDs("AEAUH", "GBLON", "GBSOU")
+	})
+
+	s.Run("should delete all ports when replaced with nothing", func() {
+		// when
+		created, deleted, err := s.repo.ReplacePorts(ctx, nil, nil)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Empty(created)
+		s.Assert().Equal([]string{"AEAUH", "GBLON", "GBSOU"}, deleted)
+		s.assertStoredIDs()
+	})
+}
+
+func (s *repositorySuite) TestUpdatingPorts() {
+	ctx := context.Background()
+
+	s.Run("shouldn't create not existing port", func() {
+		// when
+		err := s.repo.UpdatePort(ctx, createPort("AEAJM"))
+
+		// then
+		s.Assert().ErrorIs(err, domainPort.ErrNotFound)
+		s.assertStoredIDs()
+	})
+
+	s.Run("should replace existing port", func() {
+		// given
+		s.Require().NoError(s.repo.CreatePort(ctx, createPort("AEAJM")))
+		updatedPort := createPort("AEAJM")
+		updatedPort.Timezone = "Europe/London"
+
+		// when
+		err := s.repo.UpdatePort(ctx, updatedPort)
+
+		// then
+		s.Require().NoError(err)
+		s.assertStored(updatedPort)
+	})
+}
+
+func (s *repositorySuite) TestDeletingPorts() {
+	ctx := context.Background()
+
+	s.Run("should fail deleting not existing port", func() {
+		// when
+		err := s.repo.DeletePort(ctx, "AEAJM")
+
+		// then
+		s.Assert().ErrorIs(err, domainPort.ErrNotFound)
+	})
+
+	s.Run("should delete existing port", func() {
+		// given
+		s.Require().NoError(s.repo.CreatePort(ctx, createPort("AEAJM")))
+		s.Require().NoError(s.repo.CreatePort(ctx, createPort("AEAUH")))
+
+		// when
+		err := s.repo.DeletePort(ctx, "AEAJM")
+
+		// then
+		s.Require().NoError(err)
+		_, err = s.repo.GetPort(ctx, "AEAJM")
+		s.Assert().ErrorIs(err, domainPort.ErrNotFound)
+		s.assertStoredIDs("AEAUH")
+	})
+}
+
+func (s *repositorySuite) TestFetchingPorts() {
+	ctx := context.Background()
+
+	s.Run("should fail fetching not existing port", func() {
+		// when
+		_, err := s.repo.GetPort(ctx, "AEAJM")
+
+		// then
+		s.Assert().ErrorIs(err, domainPort.ErrNotFound)
+	})
+
+	s.Run("should return empty list when there are no ports", func() {
+		// when
+		ports, nextPageToken, err := s.repo.GetPorts(ctx, domainPort.Query{})
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Empty(ports)
+		s.Assert().Empty(nextPageToken)
+	})
+
+	s.Run("should fail fetching ports with invalid page token", func() {
+		// when
+		_, _, err := s.repo.GetPorts(ctx, domainPort.Query{PageSize: 1, PageToken: "%%%"})
+
+		// then
+		s.Assert().ErrorIs(err, domainPort.ErrInvalidPageToken)
+	})
+}
+
+func (s *repositorySuite) TestOrderingPorts() {
+	ctx := context.Background()
+	for _, id := range []string{"AEDXB", "AEAJM", "AEKLF", "AEAUH", "AEFJR"} {
+		s.Require().NoError(s.repo.CreatePort(ctx, createPort(id)))
+	}
+
+	s.Run("should return all ports ordered by id", func() {
+		s.assertStoredIDs("AEAJM", "AEAUH", "AEDXB", "AEFJR", "AEKLF")
+	})
+
+	s.Run("should split ports into consecutive pages", func() {
+		// when
+		ids := s.fetchIDsPageByPage(domainPort.Query{PageSize: 2}, nil)
+
+		// then
+		s.Assert().Equal([]string{"AEAJM", "AEAUH", "AEDXB", "AEFJR", "AEKLF"}, ids)
+	})
+
+	s.Run("should continue paging when ports change in between pages", func() {
+		// when port from the first page is deleted and port after the current page is added
+		ids := s.fetchIDsPageByPage(domainPort.Query{PageSize: 2}, func() {
+			s.Require().NoError(s.repo.DeletePort(ctx, "AEAJM"))
+			s.Require().NoError(s.repo.CreatePort(ctx, createPort("AEZZZ")))
+		})
+
+		// then
+		s.Assert().Equal([]string{"AEAJM", "AEAUH", "AEDXB", "AEFJR", "AEKLF", "AEZZZ"}, ids)
+	})
+}
+
+func (s *repositorySuite) TestOrderingPortsByFields() {
+	ctx := context.Background()
+	for _, port := range []struct{ id, name, country string }{
+		{id: "AEKLF", name: "Khor al Fakkan", country: "United Arab Emirates"},
+		{id: "AEAJM", name: "Ajman", country: "United Arab Emirates"},
+		{id: "PLGDN", name: "Gdansk", country: "Poland"},
+		{id: "GBLON", name: "London", country: "United Kingdom"},
+		{id: "PLGDY", name: "Gdansk", country: "Poland"},
+	} {
+		newPort := createPort(port.id)
+		newPort.Name = port.name
+		newPort.Country = port.country
+		s.Require().NoError(s.repo.CreatePort(ctx, newPort))
+	}
+	// updating the port doesn't change its insertion time
+	updatedPort, err := s.repo.GetPort(ctx, "AEKLF")
+	s.Require().NoError(err)
+	updatedPort.Timezone = "Europe/London"
+	_, err = s.repo.UpsertPort(ctx, updatedPort)
+	s.Require().NoError(err)
+
+	tests := []struct {
+		name     string
+		query    domainPort.Query
+		expected []string
+	}{
+		{
+			name:     "should order ports by id in descending order",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByID, Descending: true},
+			expected: []string{"PLGDY", "PLGDN", "GBLON", "AEKLF", "AEAJM"},
+		},
+		{
+			name:     "should order ports by name using id for equal names",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByName},
+			expected: []string{"AEAJM", "PLGDN", "PLGDY", "AEKLF", "GBLON"},
+		},
+		{
+			name:     "should order ports by name in descending order",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByName, Descending: true},
+			expected: []string{"GBLON", "AEKLF", "PLGDY", "PLGDN", "AEAJM"},
+		},
+		{
+			name:     "should order ports by country using id for equal countries",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByCountry},
+			expected: []string{"PLGDN", "PLGDY", "AEAJM", "AEKLF", "GBLON"},
+		},
+		{
+			name:     "should order ports by country in descending order",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByCountry, Descending: true},
+			expected: []string{"GBLON", "AEKLF", "AEAJM", "PLGDY", "PLGDN"},
+		},
+		{
+			name:     "should order ports by insertion time",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByInsertionTime},
+			expected: []string{"AEKLF", "AEAJM", "PLGDN", "GBLON", "PLGDY"},
+		},
+		{
+			name:     "should order ports by insertion time in descending order",
+			query:    domainPort.Query{OrderBy: domainPort.OrderByInsertionTime, Descending: true},
+			expected: []string{"PLGDY", "GBLON", "PLGDN", "AEAJM", "AEKLF"},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			// when
+			ports, nextPageToken, err := s.repo.GetPorts(ctx, test.query)
+
+			// then
+			s.Require().NoError(err)
+			s.Assert().Empty(nextPageToken)
+			s.Assert().Equal(test.expected, portIDs(ports))
+		})
+
+		s.Run(test.name+" page by page", func() {
+			// when
+			for _, pageSize := range []int{1, 2, 4} {
+				query := test.query
+				query.PageSize = pageSize
+				ids := s.fetchIDsPageByPage(query, nil)
+
+				// then
+				s.Assert().Equal(test.expected, ids, "page size %d", pageSize)
+			}
+		})
+	}
+
+	s.Run("should reject page token issued for different order", func() {
+		// given
+		_, nextPageToken, err := s.repo.GetPorts(ctx, domainPort.Query{OrderBy: domainPort.OrderByName, PageSize: 1})
+		s.Require().NoError(err)
+		s.Require().NotEmpty(nextPageToken)
+
+		// when
+		_, _, nameDescErr := s.repo.GetPorts(ctx, domainPort.Query{
+			OrderBy:    domainPort.OrderByName,
+			Descending: true,
+			PageSize:   1,
+			PageToken:  nextPageToken,
+		})
+		_, _, countryErr := s.repo.GetPorts(ctx, domainPort.Query{
+			OrderBy:   domainPort.OrderByCountry,
+			PageSize:  1,
+			PageToken: nextPageToken,
+		})
+
+		// then
+		s.Assert().ErrorIs(nameDescErr, domainPort.ErrInvalidPageToken)
+		s.Assert().ErrorIs(countryErr, domainPort.ErrInvalidPageToken)
+	})
+}
+
+func (s *repositorySuite) TestFilteringPorts() {
+	ctx := context.Background()
+	for _, port := range []struct{ id, city, country, province, timezone, code, region string }{
+		{id: "AEAJM", city: "Ajman", country: "United Arab Emirates", province: "Ajman", timezone: "Asia/Dubai", code: "52000", region: "Middle East"},
+		{id: "AEAUH", city: "Abu Dhabi", country: "United Arab Emirates", province: "Abu Dhabi", timezone: "Asia/Dubai", code: "52001", region: "Middle East"},
+		{id: "GBLON", city: "London", country: "United Kingdom", province: "England", timezone: "Europe/London", code: "41000", region: "Europe"},
+		{id: "PLGDN", city: "Gdansk", country: "Poland", province: "Pomorskie", timezone: "Europe/Warsaw", code: "45100", region: "Europe"},
+		{id: "PLGDY", city: "Gdynia", country: "Poland", province: "Pomorskie", timezone: "Europe/Warsaw", code: "45101", region: "Baltic"},
+	} {
+		newPort := createPort(port.id)
+		newPort.City = port.city
+		newPort.Country = port.country
+		newPort.Province = port.province
+		newPort.Timezone = port.timezone
+		newPort.Code = port.code
+		newPort.Regions = []string{port.region, "World"}
+		newPort.Unlocs = []string{port.id, port.id[:2] + "XXX"}
+		s.Require().NoError(s.repo.CreatePort(ctx, newPort))
+	}
+
+	tests := []struct {
+		name     string
+		filter   domainPort.Filter
+		expected []string
+	}{
+		{name: "should filter ports by country", filter: domainPort.Filter{Country: "Poland"}, expected: []string{"PLGDN", "PLGDY"}},
+		{name: "should filter ports by province", filter: domainPort.Filter{Province: "Abu Dhabi"}, expected: []string{"AEAUH"}},
+		{name: "should filter ports by city", filter: domainPort.Filter{City: "London"}, expected: []string{"GBLON"}},
+		{name: "should filter ports by timezone", filter: domainPort.Filter{Timezone: "Asia/Dubai"}, expected: []string{"AEAJM", "AEAUH"}},
+		{name: "should filter ports by code", filter: domainPort.Filter{Code: "45101"}, expected: []string{"PLGDY"}},
+		{name: "should filter ports by any of regions", filter: domainPort.Filter{Region: "Europe"}, expected: []string{"GBLON", "PLGDN"}},
+		{name: "should filter ports by any of unlocs", filter: domainPort.Filter{Unloc: "PLXXX"}, expected: []string{"PLGDN", "PLGDY"}},
+		{name: "should filter ports ignoring case", filter: domainPort.Filter{Country: "poland", Region: "BALTIC"}, expected: []string{"PLGDY"}},
+		{name: "should require all conditions to match", filter: domainPort.Filter{Country: "Poland", Region: "Europe"}, expected: []string{"PLGDN"}},
+		{name: "should return no ports when none matches", filter: domainPort.Filter{Country: "Chile"}, expected: []string{}},
+	}
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			// when
+			ports, _, err := s.repo.GetPorts(ctx, domainPort.Query{Filter: test.filter})
+			pagedIDs := s.fetchIDsPageByPage(domainPort.Query{Filter: test.filter, PageSize: 1}, nil)
+
+			// then
+			s.Require().NoError(err)
+			s.Assert().Equal(test.expected, portIDs(ports))
+			s.Assert().Equal(test.expected, pagedIDs)
+		})
+	}
+
+	s.Run("should filter ports in requested order", func() {
+		// when
+		ids := s.fetchIDsPageByPage(domainPort.Query{
+			Filter:     domainPort.Filter{Region: "World"},
+			OrderBy:    domainPort.OrderByCountry,
+			Descending: true,
+			PageSize:   2,
+		}, nil)
+
+		// then
+		s.Assert().Equal([]string{"GBLON", "AEAUH", "AEAJM", "PLGDY", "PLGDN"}, ids)
+	})
+}
+
+func (s *repositorySuite) TestNilPorts() {
+	ctx := context.Background()
+
+	s.Assert().Error(s.repo.CreatePort(ctx, nil))
+	_, err := s.repo.UpsertPort(ctx, nil)
+	s.Assert().Error(err)
+	s.Assert().Error(s.repo.UpdatePort(ctx, nil))
+	s.assertStoredIDs()
+}
+
+func (s *repositorySuite) TestConcurrentWriters() {
+	ctx := context.Background()
+
+	s.Run("should store ports written concurrently", func() {
+		// when
+		var wg sync.WaitGroup
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s.Assert().NoError(s.repo.CreatePort(ctx, createPort(fmt.Sprintf("AE%03d", i))))
+			}(i)
+		}
+		wg.Wait()
+
+		// then
+		ports, _, err := s.repo.GetPorts(ctx, domainPort.Query{})
+		s.Require().NoError(err)
+		s.Assert().Len(ports, concurrentWriters)
+	})
+
+	s.Run("should create port only once when it's created concurrently", func() {
+		// when
+		var (
+			wg      sync.WaitGroup
+			mutex   sync.Mutex
+			created int
+		)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := s.repo.CreatePort(ctx, createPort("AEAJM"))
+				if err == nil {
+					mutex.Lock()
+					created++
+					mutex.Unlock()
+					return
+				}
+				s.Assert().ErrorIs(err, domainPort.ErrAlreadyExists)
+			}()
+		}
+		wg.Wait()
+
+		// then
+		s.Assert().Equal(1, created)
+	})
+
+	s.Run("should report exactly one upsert as created when port is upserted concurrently", func() {
+		// when
+		var (
+			wg      sync.WaitGroup
+			mutex   sync.Mutex
+			created int
+		)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				isCreated, err := s.repo.UpsertPort(ctx, createPort("AEAUH"))
+				s.Assert().NoError(err)
+				if isCreated {
+					mutex.Lock()
+					created++
+					mutex.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		// then
+		s.Assert().Equal(1, created)
+		s.assertStored(createPort("AEAUH"))
+	})
+}
+
+func (s *repositorySuite) TestContextCancellation() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Require().NoError(s.repo.CreatePort(context.Background(), createPort("AEAUH")))
+
+	s.Assert().ErrorIs(s.repo.CreatePort(ctx, createPort("AEAJM")), context.Canceled)
+	_, err := s.repo.UpsertPort(ctx, createPort("AEAJM"))
+	s.Assert().ErrorIs(err, context.Canceled)
+	_, err = s.repo.UpsertPorts(ctx, []*domainPort.Port{createPort("AEAJM")})
+	s.Assert().ErrorIs(err, context.Canceled)
+	_, _, err = s.repo.ReplacePorts(ctx, []*domainPort.Port{createPort("AEAJM")}, nil)
+	s.Assert().ErrorIs(err, context.Canceled)
+	s.Assert().ErrorIs(s.repo.UpdatePort(ctx, createPort("AEAUH")), context.Canceled)
+	s.Assert().ErrorIs(s.repo.DeletePort(ctx, "AEAUH"), context.Canceled)
+	_, err = s.repo.GetPort(ctx, "AEAUH")
+	s.Assert().ErrorIs(err, context.Canceled)
+	_, _, err = s.repo.GetPorts(ctx, domainPort.Query{})
+	s.Assert().ErrorIs(err, context.Canceled)
+
+	// none of the writes is applied
+	s.assertStoredIDs("AEAUH")
+	s.assertStored(createPort("AEAUH"))
+}
+
+// fetchIDsPageByPage fetches IDs of all ports matching the query page by page, calling
+// afterFirstPage once the first page is fetched.
+func (s *repositorySuite) fetchIDsPageByPage(query domainPort.Query, afterFirstPage func()) []string {
+	ids := make([]string, 0)
+	for {
+		ports, nextPageToken, err := s.repo.GetPorts(context.Background(), query)
+		s.Require().NoError(err)
+		s.Require().LessOrEqual(len(ports), query.PageSize)
+		ids = append(ids, portIDs(ports)...)
+		if nextPageToken == "" {
+			return ids
+		}
+		if query.PageToken == "" && afterFirstPage != nil {
+			afterFirstPage()
+		}
+		query.PageToken = nextPageToken
+	}
+}
+
+func (s *repositorySuite) assertStored(expected *domainPort.Port) {
+	port, err := s.repo.GetPort(context.Background(), expected.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(expected, port)
+}
+
+func (s *repositorySuite) assertStoredIDs(expected ...string) {
+	ports, _, err := s.repo.GetPorts(context.Background(), domainPort.Query{})
+	s.Require().NoError(err)
+	ids := portIDs(ports)
+	s.Assert().True(sort.StringsAreSorted(ids), "ports aren't ordered by id: %v", ids)
+	if expected == nil {
+		expected = []string{}
+	}
+	s.Assert().Equal(expected, ids)
+}
+
+func portIDs(ports []*domainPort.Port) []string {
+	ids := make([]string, len(ports))
+	for i, port := range ports {
+		ids[i] = port.ID
+	}
+	return ids
+}
+
+// createPort creates a port with all fields set, as repositories aren't required to
+// distinguish nil and empty lists.
+func createPort(id string) *domainPort.Port {
+	return &domainPort.Port{
+		ID:       id,
+		Name:     "name",
+		City:     "city",
+		Country:  "United Arab Emirates",
+		Alias:    []string{"alias"},
+		Regions:  []string{"region"},
+		Location: &domainPort.GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+		Province: "province",
+		Timezone: "Asia/Dubai",
+		Unlocs:   []string{id},
+		Code:     "52000",
+	}
+}
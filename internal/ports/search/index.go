@@ -0,0 +1,354 @@
+// Package search provides in-memory full-text index of ports used for type-ahead search.
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+// field weights make matches of port name more relevant than matches of its aliases
+const (
+	nameWeight  = 1.0
+	unlocWeight = 1.0
+	cityWeight  = 0.8
+	aliasWeight = 0.6
+	// province often holds local name of the port, i.e. "Abu Z¸aby [Abu Dhabi]"
+	provinceWeight = 0.4
+)
+
+// match scores of a single query token
+const (
+	exactScore       = 1.0
+	prefixScore      = 0.8
+	fuzzyScore       = 0.6
+	fuzzyPrefixScore = 0.5
+	// editPenalty lowers the score of fuzzy match for every edit
+	editPenalty = 0.1
+)
+
+// Index matches ports by words of their name, city, aliases, unlocs and province. Every word of the query
+// has to match a word of the port either exactly, as its prefix or with a few typos.
+// It's safe for concurrent use.
+type Index struct {
+	mutex sync.RWMutex
+	ports map[string]*indexedPort
+	// postings maps every indexed word to IDs of ports having it, along with the highest
+	// weight of a field in which the word occurs
+	postings map[string]map[string]float64
+	// words are sorted keys of postings, so words with given prefix can be found quickly
+	words []string
+}
+
+type indexedPort struct {
+	port  *domainPort.Port
+	words map[string]float64
+}
+
+func NewIndex() *Index {
+	return &Index{
+		ports:    make(map[string]*indexedPort),
+		postings: make(map[string]map[string]float64),
+	}
+}
+
+// Add indexes the port, replacing previously indexed port with the same ID.
+func (i *Index) Add(port *domainPort.Port) {
+	words := make(map[string]float64)
+	addWords := func(weight float64, texts ...string) {
+		for _, text := range texts {
+			for _, word := range Tokenize(text) {
+				if words[word] < weight {
+					words[word] = weight
+				}
+			}
+		}
+	}
+	addWords(nameWeight, port.Name)
+	addWords(unlocWeight, port.Unlocs...)
+	addWords(cityWeight, port.City)
+	addWords(aliasWeight, port.Alias...)
+	addWords(provinceWeight, port.Province)
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.remove(port.ID)
+	i.ports[port.ID] = &indexedPort{port: port, words: words}
+	for word, weight := range words {
+		portIDs, ok := i.postings[word]
+		if !ok {
+			portIDs = make(map[string]float64)
+			i.postings[word] = portIDs
+			i.insertWord(word)
+		}
+		portIDs[port.ID] = weight
+	}
+}
+
+// Remove removes the port from the index, it's a no-op when port isn't indexed.
+func (i *Index) Remove(id string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.remove(id)
+}
+
+// Search returns at most limit ports matching all words of the query, the most relevant first.
+func (i *Index) Search(query string, limit int) []*domainPort.Port {
+	queryWords := Tokenize(query)
+	if len(queryWords) == 0 || limit <= 0 {
+		return []*domainPort.Port{}
+	}
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	var scores map[string]float64
+	for _, queryWord := range queryWords {
+		wordScores := i.matchWord(queryWord)
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		// port has to match every word of the query
+		for id, score := range scores {
+			wordScore, ok := wordScores[id]
+			if !ok {
+				delete(scores, id)
+				continue
+			}
+			scores[id] = score + wordScore
+		}
+	}
+
+	ports := make([]*domainPort.Port, 0, len(scores))
+	for id := range scores {
+		ports = append(ports, i.ports[id].port)
+	}
+	sort.Slice(ports, func(a, b int) bool {
+		scoreA, scoreB := scores[ports[a].ID], scores[ports[b].ID]
+		if scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		if ports[a].Name != ports[b].Name {
+			return ports[a].Name < ports[b].Name
+		}
+		return ports[a].ID < ports[b].ID
+	})
+	if len(ports) > limit {
+		ports = ports[:limit]
+	}
+	return ports
+}
+
+// matchWord returns scores of ports matching the query word. When the word matches a port in
+// a few ways, the best score is taken.
+func (i *Index) matchWord(queryWord string) map[string]float64 {
+	scores := make(map[string]float64)
+	addMatch := func(word string, score float64) {
+		for id, weight := range i.postings[word] {
+			if scores[id] < score*weight {
+				scores[id] = score * weight
+			}
+		}
+	}
+
+	// words having query word as a prefix are next to each other in sorted words
+	start := sort.SearchStrings(i.words, queryWord)
+	for _, word := range i.words[start:] {
+		if !strings.HasPrefix(word, queryWord) {
+			break
+		}
+		if word == queryWord {
+			addMatch(word, exactScore)
+		} else {
+			addMatch(word, prefixScore)
+		}
+	}
+
+	maxEdits := allowedEdits(queryWord)
+	if maxEdits == 0 {
+		return scores
+	}
+	i.matchTypos([]rune(queryWord), maxEdits, addMatch)
+	return scores
+}
+
+// matchTypos finds words typed with at most maxEdits typos, either whole or as their prefix. Sorted words
+// are walked like a trie: rows of the distance matrix are shared by words with common prefix, and all words
+// starting with a prefix which is already too far from the query are skipped at once. Only words at most
+// maxEdits longer than the query are compared whole, longer ones only by their prefix of query length.
+func (i *Index) matchTypos(query []rune, maxEdits int, addMatch func(word string, score float64)) {
+	matrix := newDistanceMatrix(query)
+	var runes, prevRunes []rune
+	for pos := 0; pos < len(i.words); {
+		word := i.words[pos]
+		runes = appendRunes(runes[:0], word)
+		length := len(runes)
+		depth := length
+		if depth > len(query)+maxEdits {
+			depth = len(query)
+		}
+		// rows of the prefix shared with the previous word are already computed
+		computed := commonPrefix(runes[:depth], prevRunes[:minInt(len(prevRunes), matrix.depth)])
+		pruned := false
+		for ; computed < depth; computed++ {
+			if matrix.next(computed+1, runes) > maxEdits {
+				pruned = true
+				break
+			}
+		}
+		matrix.depth = computed
+		prevRunes, runes = runes, prevRunes
+
+		if pruned && computed < len(query) {
+			// no word starting with the same prefix can match
+			prefix := runePrefix(word, computed+1)
+			pos += sort.Search(len(i.words)-pos, func(j int) bool {
+				return !strings.HasPrefix(i.words[pos+j], prefix)
+			})
+			continue
+		}
+		pos++
+
+		// whole word is compared only when all its rows are computed
+		if length == computed {
+			if edits := matrix.distance(length); edits > 0 && edits <= maxEdits {
+				addMatch(word, fuzzyScore-float64(edits)*editPenalty)
+				continue
+			}
+		}
+		// prefix of the word typed with typos
+		if length > len(query) {
+			if edits := matrix.distance(len(query)); edits > 0 && edits <= maxEdits {
+				addMatch(word, fuzzyPrefixScore-float64(edits)*editPenalty)
+			}
+		}
+	}
+}
+
+// remove must be called with write lock held.
+func (i *Index) remove(id string) {
+	indexed, ok := i.ports[id]
+	if !ok {
+		return
+	}
+	delete(i.ports, id)
+	for word := range indexed.words {
+		delete(i.postings[word], id)
+		if len(i.postings[word]) == 0 {
+			delete(i.postings, word)
+			i.removeWord(word)
+		}
+	}
+}
+
+func (i *Index) insertWord(word string) {
+	pos := sort.SearchStrings(i.words, word)
+	i.words = append(i.words, "")
+	copy(i.words[pos+1:], i.words[pos:])
+	i.words[pos] = word
+}
+
+func (i *Index) removeWord(word string) {
+	pos := sort.SearchStrings(i.words, word)
+	if pos < len(i.words) && i.words[pos] == word {
+		i.words = append(i.words[:pos], i.words[pos+1:]...)
+	}
+}
+
+// allowedEdits returns number of typos tolerated in the word, short words have to be typed
+// correctly as they would match too many other words otherwise.
+func allowedEdits(word string) int {
+	switch length := len([]rune(word)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distanceMatrix is an optimal string alignment distance matrix of the query and words, which counts
+// insertions, deletions, substitutions and transpositions of adjacent characters. Its rows are
+// computed for characters of a word one by one, so rows of a prefix can be reused by the next word.
+type distanceMatrix struct {
+	query []rune
+	// rows[d][j] is the distance between the first d characters of the word and the first j of the query
+	rows [][]int
+	// depth is the number of rows computed for the current word, apart from the first one
+	depth int
+}
+
+func newDistanceMatrix(query []rune) *distanceMatrix {
+	first := make([]int, len(query)+1)
+	for j := range first {
+		first[j] = j
+	}
+	return &distanceMatrix{query: query, rows: [][]int{first}}
+}
+
+// next computes the row of the first d characters of the word and returns its minimum, which
+// never decreases in the following rows.
+func (m *distanceMatrix) next(d int, word []rune) int {
+	if len(m.rows) <= d {
+		m.rows = append(m.rows, make([]int, len(m.query)+1))
+	}
+	prev, current := m.rows[d-1], m.rows[d]
+	current[0] = d
+	rowMin := current[0]
+	for j := 1; j <= len(m.query); j++ {
+		cost := 1
+		if word[d-1] == m.query[j-1] {
+			cost = 0
+		}
+		current[j] = minInt(prev[j]+1, current[j-1]+1, prev[j-1]+cost)
+		if d > 1 && j > 1 && word[d-1] == m.query[j-2] && word[d-2] == m.query[j-1] {
+			current[j] = minInt(current[j], m.rows[d-2][j-2]+1)
+		}
+		rowMin = minInt(rowMin, current[j])
+	}
+	return rowMin
+}
+
+// distance returns the distance between the query and the first d characters of the word.
+func (m *distanceMatrix) distance(d int) int {
+	return m.rows[d][len(m.query)]
+}
+
+func appendRunes(runes []rune, word string) []rune {
+	for _, r := range word {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+func commonPrefix(a, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// runePrefix returns the first n characters of the word.
+func runePrefix(word string, n int) string {
+	for pos := range word {
+		if n == 0 {
+			return word[:pos]
+		}
+		n--
+	}
+	return word
+}
+
+func minInt(first int, values ...int) int {
+	for _, value := range values {
+		if value < first {
+			first = value
+		}
+	}
+	return first
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+)
+
+func TestTokenizing(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected []string
+	}{
+		"should lower case and split words": {
+			text:     "Khor al Fakkan",
+			expected: []string{"khor", "al", "fakkan"},
+		},
+		"should remove combining diacritics": {
+			text:     "Gdańsk Świnoujście",
+			expected: []string{"gdansk", "swinoujscie"},
+		},
+		"should remove spacing diacritics": {
+			text:     "Abu Z¸aby [Abu Dhabi]",
+			expected: []string{"abu", "zaby", "abu", "dhabi"},
+		},
+		"should fold letters without decomposition": {
+			text:     "Łódź Tromsø",
+			expected: []string{"lodz", "tromso"},
+		},
+		"should keep apostrophes inside words": {
+			text:     "Ra's al-Khaimah",
+			expected: []string{"ras", "al", "khaimah"},
+		},
+		"should return no words for punctuation": {
+			text:     " -, ",
+			expected: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			tokens := Tokenize(tc.text)
+
+			// then
+			assert.Equal(t, tc.expected, tokens)
+		})
+	}
+}
+
+func TestSearching(t *testing.T) {
+	index := NewIndex()
+	for _, port := range []*domainPort.Port{
+		{ID: "AEAUH", Name: "Abu Dhabi", City: "Abu Z¸aby", Unlocs: []string{"AEAUH"}},
+		{ID: "AEAJM", Name: "Ajman", City: "Ajman", Unlocs: []string{"AEAJM"}},
+		{ID: "PLGDN", Name: "Gdańsk", City: "Gdańsk", Alias: []string{"Danzig"}, Unlocs: []string{"PLGDN"}},
+		{ID: "PLGDY", Name: "Gdynia", City: "Gdynia", Unlocs: []string{"PLGDY"}},
+		{ID: "GBLON", Name: "London", City: "London", Unlocs: []string{"GBLON"}},
+	} {
+		index.Add(port)
+	}
+
+	tests := map[string]struct {
+		query    string
+		expected []string
+	}{
+		"should find port by exact name":                 {query: "London", expected: []string{"GBLON"}},
+		"should find ports by name prefix":               {query: "gd", expected: []string{"PLGDN", "PLGDY"}},
+		"should find port ignoring diacritics of query":  {query: "Gdansk", expected: []string{"PLGDN"}},
+		"should find port ignoring diacritics of port":   {query: "abu zaby", expected: []string{"AEAUH"}},
+		"should find port by alias":                      {query: "danzig", expected: []string{"PLGDN"}},
+		"should find port by unloc":                      {query: "aeajm", expected: []string{"AEAJM"}},
+		"should find port with typo":                     {query: "Lodnon", expected: []string{"GBLON"}},
+		"should find port by prefix with typo":           {query: "gdasn", expected: []string{"PLGDN"}},
+		"should find port with typo in first letter":     {query: "Kondon", expected: []string{"GBLON"}},
+		"should require all words of the query to match": {query: "abu ajman", expected: []string{}},
+		"shouldn't match words with too many typos":      {query: "gdynia", expected: []string{"PLGDY"}},
+		"should not tolerate typos in short words":       {query: "lnd", expected: []string{}},
+		"should return nothing for empty query":          {query: " ", expected: []string{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			ports := index.Search(tc.query, 10)
+
+			// then
+			assert.Equal(t, tc.expected, ids(ports))
+		})
+	}
+
+	t.Run("should return at most limit ports", func(t *testing.T) {
+		// when
+		ports := index.Search("gd", 1)
+
+		// then
+		assert.Equal(t, []string{"PLGDN"}, ids(ports))
+	})
+}
+
+func TestUpdatingIndex(t *testing.T) {
+	t.Run("should replace indexed port", func(t *testing.T) {
+		// given
+		index := NewIndex()
+		index.Add(&domainPort.Port{ID: "PLGDN", Name: "Danzig"})
+
+		// when
+		index.Add(&domainPort.Port{ID: "PLGDN", Name: "Gdansk"})
+
+		// then
+		assert.Empty(t, index.Search("danzig", 10))
+		assert.Equal(t, []string{"PLGDN"}, ids(index.Search("gdansk", 10)))
+	})
+
+	t.Run("should remove port", func(t *testing.T) {
+		// given
+		index := NewIndex()
+		index.Add(&domainPort.Port{ID: "PLGDN", Name: "Gdansk"})
+		index.Add(&domainPort.Port{ID: "PLGDY", Name: "Gdynia"})
+
+		// when
+		index.Remove("PLGDN")
+		index.Remove("unknown")
+
+		// then
+		assert.Equal(t, []string{"PLGDY"}, ids(index.Search("gd", 10)))
+		assert.NotContains(t, index.words, "gdansk")
+	})
+}
+
+func ids(ports []*domainPort.Port) []string {
+	result := make([]string, len(ports))
+	for i, port := range ports {
+		result[i] = port.ID
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldedLetters replaces letters which aren't decomposed into base letter and diacritic mark.
+var foldedLetters = map[rune]string{
+	'ł': "l",
+	'ø': "o",
+	'đ': "d",
+	'ð': "d",
+	'ı': "i",
+	'ß': "ss",
+	'æ': "ae",
+	'œ': "oe",
+	'þ': "th",
+}
+
+// Tokenize splits the text into lower case words without diacritics, so "Abu Z¸aby" and
+// "abu zaby" give the same tokens.
+func Tokenize(text string) []string {
+	var (
+		tokens  []string
+		builder strings.Builder
+	)
+	flush := func() {
+		if builder.Len() > 0 {
+			tokens = append(tokens, builder.String())
+			builder.Reset()
+		}
+	}
+
+	for _, r := range norm.NFD.String(text) {
+		r = unicode.ToLower(r)
+		switch {
+		// combining marks are diacritics separated from letters by decomposition, modifier
+		// symbols like spacing cedilla are diacritics written as separate characters
+		case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Sk, r), r == '\'', r == '’':
+			continue
+		case foldedLetters[r] != "":
+			builder.WriteString(foldedLetters[r])
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			builder.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
@@ -2,28 +2,82 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
 
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	pb2 "github.com/arturskrzydlo/ports/internal/common/pb"
 
 	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
+	"github.com/arturskrzydlo/ports/internal/ports/geo"
+	"github.com/arturskrzydlo/ports/internal/ports/search"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+	// streamBatchSize is number of ports fetched from repository at once while streaming
+	streamBatchSize = 500
+
+	defaultSearchLimit = 10
+	maxSearchLimit     = 100
+
+	defaultNearestPorts = 10
+	maxNearestPorts     = 100
 )
 
 type APIServer struct {
 	pb2.UnimplementedPortServiceServer
 	log  *zap.Logger
 	repo Repository
+
+	// indexMutex serializes repository writes together with updates of indexes, so indexes
+	// always hold the same version of a port as the repository
+	indexMutex  sync.Mutex
+	searchIndex *search.Index
+	geoIndex    *geo.Index
 }
 
 // TODO: this service could be separated out from grpc service to have a service layer separate
 // from api layer - however it would be really thin in this case
 func NewPortsService(log *zap.Logger, repo Repository) *APIServer {
 	return &APIServer{
-		log:  log,
-		repo: repo,
+		log:         log,
+		repo:        repo,
+		searchIndex: search.NewIndex(),
+		geoIndex:    geo.NewIndex(),
+	}
+}
+
+// LoadIndexes builds indexes from ports already stored in the repository. It must be called
+// before the server starts handling requests.
+func (s *APIServer) LoadIndexes(ctx context.Context) error {
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+
+	query := domainPort.Query{PageSize: streamBatchSize}
+	for {
+		ports, nextPageToken, err := s.repo.GetPorts(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to fetch ports to index: %w", err)
+		}
+		for _, port := range ports {
+			s.indexPort(port)
+		}
+		if nextPageToken == "" {
+			return nil
+		}
+		query.PageToken = nextPageToken
 	}
 }
 
@@ -31,26 +85,607 @@ func (s *APIServer) CreatePort(ctx context.Context, req *pb2.CreatePortRequest)
 	s.log.Debug("creating port", zap.Any("port", req.Port))
 	port, err := portPBToPort(req.Port)
 	if err != nil {
-		return &emptypb.Empty{}, fmt.Errorf("failed to create port:%w", err)
+		return nil, statusErr("failed to create port", err)
+	}
+
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+	if err = s.repo.CreatePort(ctx, port); err != nil {
+		return nil, statusErr("failed to store port "+port.ID, err)
+	}
+	s.indexPort(port)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *APIServer) UpdatePort(ctx context.Context, req *pb2.UpdatePortRequest) (*emptypb.Empty, error) {
+	s.log.Debug("updating port", zap.Any("port", req.Port), zap.Strings("mask", req.GetUpdateMask().GetPaths()))
+	var (
+		port *domainPort.Port
+		err  error
+	)
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		port, err = portPBToPort(req.Port)
+		if err != nil {
+			return nil, statusErr("failed to update port", err)
+		}
+	} else {
+		port, err = s.patchPort(ctx, req.Port, req.UpdateMask.Paths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.repo.UpdatePort(ctx, port); err != nil {
+		return nil, statusErr("failed to update port "+port.ID, err)
+	}
+	s.indexPort(port)
+	return &emptypb.Empty{}, nil
+}
+
+// patchPort applies fields from the mask to the stored port. Patch isn't atomic, concurrent
+// update of the same port made between reading and storing it is overwritten.
+func (s *APIServer) patchPort(ctx context.Context, pbPort *pb2.Port, paths []string) (*domainPort.Port, error) {
+	storedPort, err := s.repo.GetPort(ctx, pbPort.GetId())
+	if err != nil {
+		return nil, statusErr("failed to fetch port to patch "+pbPort.GetId(), err)
 	}
 
-	err = s.repo.CreatePort(ctx, port)
+	location, err := locationPBToGeoPoint(pbPort)
 	if err != nil {
-		return nil, fmt.Errorf("failed to store port: %w", err)
+		return nil, statusErr("failed to patch port", err)
 	}
+	patch := &domainPort.Port{
+		ID:       pbPort.Id,
+		Name:     pbPort.Name,
+		City:     pbPort.City,
+		Country:  pbPort.Country,
+		Alias:    pbPort.Alias,
+		Regions:  pbPort.Regions,
+		Location: location,
+		Province: pbPort.Province,
+		Timezone: pbPort.Timezone,
+		Unlocs:   pbPort.Unlocs,
+		Code:     pbPort.Code,
+	}
+	port, err := storedPort.Patch(patch, paths)
+	if err != nil {
+		return nil, statusErr("failed to patch port", err)
+	}
+	return port, nil
+}
+
+func (s *APIServer) DeletePort(ctx context.Context, req *pb2.DeletePortRequest) (*emptypb.Empty, error) {
+	s.log.Debug("deleting port", zap.String("id", req.Id))
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+	if err := s.repo.DeletePort(ctx, req.Id); err != nil {
+		return nil, statusErr("failed to delete port "+req.Id, err)
+	}
+	s.unindexPort(req.Id)
 	return &emptypb.Empty{}, nil
 }
 
-func (s *APIServer) GetPorts(ctx context.Context, _ *emptypb.Empty) (*pb2.GetPortsResponse, error) {
-	s.log.Debug("fetching list of ports")
-	ports, err := s.repo.GetPorts(ctx)
+// StreamCreatePorts stores every port received on the stream, replacing already existing
+// ports with the same ID. Invalid ports are reported as soon as they're rejected and once again in the
+// summary, which is the last message sent on the stream. What happens with the rest of the stream depends
+// on the error policy from options, which can be sent only as the first message of the stream. Ports which
+// the client couldn't decode are rejected the same way as invalid ones. Atomic streams are stored in
+// a single transaction after they're received, so a stream which fails leaves ports unchanged. Replacing
+// streams delete, in the same transaction, stored ports which weren't sent on the stream, and dry run
+// streams only report what would change.
+func (s *APIServer) StreamCreatePorts(stream pb2.PortService_StreamCreatePortsServer) error {
+	s.log.Debug("receiving stream of ports")
+	var (
+		summary = &pb2.IngestSummary{}
+		policy  pb2.ErrorPolicy
+		atomic  bool
+		replace bool
+		// pending are valid ports of atomic stream waiting until the whole stream is received
+		pending []*domainPort.Port
+		// rejectedIDs are IDs of rejected ports, which are kept by replacement
+		rejectedIDs []string
+	)
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return statusErr("failed to receive port", err)
+		}
+		if opts := req.GetOptions(); opts != nil {
+			if !first {
+				return status.Error(codes.InvalidArgument, "options can be sent only as the first message")
+			}
+			policy = opts.ErrorPolicy
+			replace = opts.Replace
+			summary.DryRun = opts.DryRun
+			atomic = opts.Atomic || policy == pb2.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING || replace || opts.DryRun
+			continue
+		}
+		entry := req.GetEntry()
+		if entry == nil {
+			return status.Error(codes.InvalidArgument, "request must contain either options or port entry")
+		}
+
+		port, err := requestedPort(entry)
+		if err != nil {
+			portErr := portErrorPB(entry, err)
+			summary.Rejected++
+			summary.Errors = append(summary.Errors, portErr)
+			if id := entry.GetPort().GetId(); id != "" {
+				rejectedIDs = append(rejectedIDs, id)
+			}
+			err = stream.Send(&pb2.StreamCreatePortsResponse{
+				Response: &pb2.StreamCreatePortsResponse_Rejected{Rejected: portErr},
+			})
+			if err != nil {
+				return statusErr("failed to report rejected port", err)
+			}
+			if policy == pb2.ErrorPolicy_ERROR_POLICY_FAIL_FAST {
+				summary.Aborted = true
+				return sendSummary(stream, summary)
+			}
+			continue
+		}
+
+		if atomic {
+			pending = append(pending, port)
+			continue
+		}
+		created, err := s.upsertPort(stream.Context(), port)
+		if err != nil {
+			return statusErr("failed to store port "+port.ID, err)
+		}
+		addStoredPort(summary, port.ID, created)
+	}
+
+	if summary.Rejected > 0 && policy == pb2.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING {
+		summary.Aborted = true
+		return sendSummary(stream, summary)
+	}
+	var err error
+	switch {
+	case summary.DryRun:
+		err = s.planPorts(stream.Context(), summary, pending, replace, rejectedIDs)
+	case replace:
+		err = s.replacePorts(stream.Context(), summary, pending, rejectedIDs)
+	case len(pending) > 0:
+		err = s.upsertPorts(stream.Context(), summary, pending)
+	}
+	if err != nil {
+		return err
+	}
+	return sendSummary(stream, summary)
+}
+
+// sendSummary sends the summary as the last message of StreamCreatePorts stream.
+func sendSummary(stream pb2.PortService_StreamCreatePortsServer, summary *pb2.IngestSummary) error {
+	err := stream.Send(&pb2.StreamCreatePortsResponse{Response: &pb2.StreamCreatePortsResponse_Summary{Summary: summary}})
+	if err != nil {
+		return statusErr("failed to send summary", err)
+	}
+	return nil
+}
+
+// requestedPort returns port sent in the entry or the reason of its rejection.
+func requestedPort(entry *pb2.PortEntry) (*domainPort.Port, error) {
+	if entry.GetDecodeError() != "" {
+		return nil, errors.New(entry.DecodeError)
+	}
+	return portPBToPort(entry.GetPort())
+}
+
+func portErrorPB(entry *pb2.PortEntry, err error) *pb2.PortError {
+	return &pb2.PortError{
+		PortId: entry.GetPort().GetId(),
+		Reason: err.Error(),
+		Offset: entry.GetOffset(),
+	}
+}
+
+func addStoredPort(summary *pb2.IngestSummary, id string, created bool) {
+	if created {
+		summary.Created++
+		summary.CreatedIds = append(summary.CreatedIds, id)
+	} else {
+		summary.Updated++
+		summary.UpdatedIds = append(summary.UpdatedIds, id)
+	}
+}
+
+// DiffPorts compares every port received on the stream to the stored port with the same ID, nothing is
+// changed. Invalid ports are reported as rejected the same way as by StreamCreatePorts.
+func (s *APIServer) DiffPorts(stream pb2.PortService_DiffPortsServer) error {
+	s.log.Debug("comparing stream of ports")
+	diff := &pb2.PortsDiff{}
+	for {
+		entry, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(diff)
+		}
+		if err != nil {
+			return statusErr("failed to receive port", err)
+		}
+
+		port, err := requestedPort(entry)
+		if err != nil {
+			diff.Rejected++
+			diff.Errors = append(diff.Errors, portErrorPB(entry, err))
+			continue
+		}
+		portDiff, err := s.diffPort(stream.Context(), port)
+		if err != nil {
+			return err
+		}
+		switch {
+		case portDiff == nil:
+			diff.Unchanged++
+			continue
+		case portDiff.Status == pb2.PortDiffStatus_PORT_DIFF_STATUS_NEW:
+			diff.New++
+		default:
+			diff.Changed++
+		}
+		portDiff.Offset = entry.Offset
+		diff.Ports = append(diff.Ports, portDiff)
+	}
+}
+
+// diffPort compares the port to the stored one, it returns nil when they're equal. All fields of a port
+// which isn't stored are reported as added.
+func (s *APIServer) diffPort(ctx context.Context, port *domainPort.Port) (*pb2.PortDiff, error) {
+	diffStatus := pb2.PortDiffStatus_PORT_DIFF_STATUS_CHANGED
+	stored, err := s.repo.GetPort(ctx, port.ID)
+	if errors.Is(err, domainPort.ErrNotFound) {
+		diffStatus = pb2.PortDiffStatus_PORT_DIFF_STATUS_NEW
+		stored, err = &domainPort.Port{ID: port.ID}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch all ports: %w", err)
+		return nil, statusErr("failed to fetch port "+port.ID, err)
+	}
+
+	fields := domainPort.Diff(stored, port)
+	if len(fields) == 0 && diffStatus == pb2.PortDiffStatus_PORT_DIFF_STATUS_CHANGED {
+		return nil, nil
+	}
+	portDiff := &pb2.PortDiff{PortId: port.ID, Status: diffStatus, Fields: make([]*pb2.FieldDiff, len(fields))}
+	for i, field := range fields {
+		portDiff.Fields[i] = &pb2.FieldDiff{
+			Field:    field.Field,
+			Change:   fieldChangesPB[field.Change],
+			OldValue: fieldValueToPB(field.Old),
+			NewValue: fieldValueToPB(field.New),
+		}
+	}
+	return portDiff, nil
+}
+
+// indexPort must be called with indexMutex held.
+func (s *APIServer) indexPort(port *domainPort.Port) {
+	s.searchIndex.Add(port)
+	s.geoIndex.Add(port)
+}
+
+// unindexPort must be called with indexMutex held.
+func (s *APIServer) unindexPort(id string) {
+	s.searchIndex.Remove(id)
+	s.geoIndex.Remove(id)
+}
+
+func (s *APIServer) upsertPort(ctx context.Context, port *domainPort.Port) (bool, error) {
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+	created, err := s.repo.UpsertPort(ctx, port)
+	if err != nil {
+		return false, err
+	}
+	s.indexPort(port)
+	return created, nil
+}
+
+// upsertPorts stores all the ports in one transaction and indexes them once they're stored.
+func (s *APIServer) upsertPorts(ctx context.Context, summary *pb2.IngestSummary, ports []*domainPort.Port) error {
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+	created, err := s.repo.UpsertPorts(ctx, ports)
+	if err != nil {
+		return statusErr("failed to store ports", err)
+	}
+	for i, port := range ports {
+		s.indexPort(port)
+		addStoredPort(summary, port.ID, created[i])
+	}
+	return nil
+}
+
+// replacePorts stores all the ports and deletes the rest, except kept ones, in one transaction.
+func (s *APIServer) replacePorts(
+	ctx context.Context, summary *pb2.IngestSummary, ports []*domainPort.Port, keepIDs []string,
+) error {
+	s.indexMutex.Lock()
+	defer s.indexMutex.Unlock()
+	created, deleted, err := s.repo.ReplacePorts(ctx, ports, keepIDs)
+	if err != nil {
+		return statusErr("failed to replace ports", err)
+	}
+	for i, port := range ports {
+		s.indexPort(port)
+		addStoredPort(summary, port.ID, created[i])
+	}
+	for _, id := range deleted {
+		s.unindexPort(id)
+	}
+	summary.Deleted = uint32(len(deleted))
+	summary.DeletedIds = deleted
+	return nil
+}
+
+// planPorts reports in the summary which ports would be created, updated and deleted by storing
+// the ports, without changing anything.
+func (s *APIServer) planPorts(
+	ctx context.Context, summary *pb2.IngestSummary, ports []*domainPort.Port, replace bool, keepIDs []string,
+) error {
+	stored, err := s.storedPortIDs(ctx)
+	if err != nil {
+		return err
+	}
+	kept := make(map[string]bool, len(ports)+len(keepIDs))
+	for _, port := range ports {
+		addStoredPort(summary, port.ID, !stored[port.ID] && !kept[port.ID])
+		kept[port.ID] = true
+	}
+	if !replace {
+		return nil
+	}
+
+	for _, id := range keepIDs {
+		kept[id] = true
+	}
+	for id := range stored {
+		if !kept[id] {
+			summary.DeletedIds = append(summary.DeletedIds, id)
+		}
+	}
+	sort.Strings(summary.DeletedIds)
+	summary.Deleted = uint32(len(summary.DeletedIds))
+	return nil
+}
+
+// storedPortIDs returns IDs of all stored ports.
+func (s *APIServer) storedPortIDs(ctx context.Context) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	query := domainPort.Query{PageSize: streamBatchSize}
+	for {
+		ports, nextPageToken, err := s.repo.GetPorts(ctx, query)
+		if err != nil {
+			return nil, statusErr("failed to fetch ports", err)
+		}
+		for _, port := range ports {
+			ids[port.ID] = true
+		}
+		if nextPageToken == "" {
+			return ids, nil
+		}
+		query.PageToken = nextPageToken
+	}
+}
+
+func (s *APIServer) GetPort(ctx context.Context, req *pb2.GetPortRequest) (*pb2.GetPortResponse, error) {
+	s.log.Debug("fetching port", zap.String("id", req.Id))
+	port, err := s.repo.GetPort(ctx, req.Id)
+	if err != nil {
+		return nil, statusErr("failed to fetch port "+req.Id, err)
+	}
+	return &pb2.GetPortResponse{Port: portToPB(port)}, nil
+}
+
+func (s *APIServer) GetPorts(ctx context.Context, req *pb2.GetPortsRequest) (*pb2.GetPortsResponse, error) {
+	s.log.Debug("fetching page of ports", zap.Int32("pageSize", req.PageSize))
+	ports, nextPageToken, err := s.listPorts(ctx, req.PageSize, req.PageToken, req.Order, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &pb2.GetPortsResponse{Ports: portsToPB(ports), NextPageToken: nextPageToken}, nil
+}
+
+func (s *APIServer) ListPorts(ctx context.Context, req *pb2.ListPortsRequest) (*pb2.ListPortsResponse, error) {
+	s.log.Debug("listing page of ports", zap.Int32("pageSize", req.PageSize))
+	ports, nextPageToken, err := s.listPorts(ctx, req.PageSize, req.PageToken, req.Order, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &pb2.ListPortsResponse{Ports: portsToPB(ports), NextPageToken: nextPageToken}, nil
+}
+
+// SearchPorts finds ports in the search index, so it doesn't query the repository.
+func (s *APIServer) SearchPorts(_ context.Context, req *pb2.SearchPortsRequest) (*pb2.SearchPortsResponse, error) {
+	s.log.Debug("searching ports", zap.String("query", req.Query))
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit can't be negative")
+	}
+	if len(search.Tokenize(req.Query)) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query must contain at least one word")
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	return &pb2.SearchPortsResponse{Ports: portsToPB(s.searchIndex.Search(req.Query, limit))}, nil
+}
+
+// FindNearestPorts finds ports in the geospatial index, so it doesn't query the repository.
+func (s *APIServer) FindNearestPorts(
+	_ context.Context, req *pb2.FindNearestPortsRequest,
+) (*pb2.FindNearestPortsResponse, error) {
+	s.log.Debug("finding nearest ports", zap.Float64("lat", req.Lat), zap.Float64("lon", req.Lon))
+	if err := validateLocation(req.Lat, req.Lon); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.K < 0 {
+		return nil, status.Error(codes.InvalidArgument, "k can't be negative")
+	}
+	if req.MaxDistanceKm < 0 || math.IsNaN(req.MaxDistanceKm) {
+		return nil, status.Error(codes.InvalidArgument, "max distance can't be negative")
+	}
+
+	k := int(req.K)
+	if k == 0 {
+		k = defaultNearestPorts
+	}
+	if k > maxNearestPorts {
+		k = maxNearestPorts
+	}
+
+	nearest := s.geoIndex.Nearest(req.Lat, req.Lon, k, req.MaxDistanceKm)
+	resp := &pb2.FindNearestPortsResponse{Ports: make([]*pb2.NearbyPort, len(nearest))}
+	for i, nearby := range nearest {
+		resp.Ports[i] = &pb2.NearbyPort{Port: portToPB(nearby.Port), DistanceKm: nearby.DistanceKm}
+	}
+	return resp, nil
+}
+
+// FindPortsInBoundingBox finds ports in the geospatial index, so it doesn't query the repository.
+func (s *APIServer) FindPortsInBoundingBox(
+	_ context.Context, req *pb2.FindPortsInBoundingBoxRequest,
+) (*pb2.FindPortsInBoundingBoxResponse, error) {
+	s.log.Debug("finding ports in bounding box",
+		zap.Float64("minLat", req.MinLat), zap.Float64("minLon", req.MinLon),
+		zap.Float64("maxLat", req.MaxLat), zap.Float64("maxLon", req.MaxLon))
+	if err := validateLocation(req.MinLat, req.MinLon); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateLocation(req.MaxLat, req.MaxLon); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.MinLat > req.MaxLat {
+		return nil, status.Error(codes.InvalidArgument, "min latitude can't be greater than max latitude")
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit can't be negative")
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	ports := s.geoIndex.WithinBoundingBox(req.MinLat, req.MinLon, req.MaxLat, req.MaxLon, limit)
+	return &pb2.FindPortsInBoundingBoxResponse{Ports: portsToPB(ports)}, nil
+}
+
+func validateLocation(lat, lon float64) error {
+	if !(lat >= -90 && lat <= 90) {
+		return fmt.Errorf("latitude must be between -90 and 90, got %v", lat)
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return fmt.Errorf("longitude must be between -180 and 180, got %v", lon)
+	}
+	return nil
+}
+
+func (s *APIServer) listPorts(
+	ctx context.Context, pageSize int32, pageToken string, order *pb2.PortOrder, filter *pb2.PortFilter,
+) ([]*domainPort.Port, string, error) {
+	if pageSize < 0 {
+		return nil, "", status.Error(codes.InvalidArgument, "page size can't be negative")
+	}
+
+	query, err := orderPBToQuery(order)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	query.PageSize = int(pageSize)
+	if query.PageSize == 0 {
+		query.PageSize = defaultPageSize
+	}
+	if query.PageSize > maxPageSize {
+		query.PageSize = maxPageSize
+	}
+	query.PageToken = pageToken
+	query.Filter = filterPBToFilter(filter)
+
+	ports, nextPageToken, err := s.repo.GetPorts(ctx, query)
+	if err != nil {
+		return nil, "", statusErr("failed to fetch ports", err)
+	}
+	return ports, nextPageToken, nil
+}
+
+func (s *APIServer) StreamPorts(req *pb2.StreamPortsRequest, stream pb2.PortService_StreamPortsServer) error {
+	s.log.Debug("streaming all ports")
+	query, err := orderPBToQuery(req.Order)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	query.PageSize = streamBatchSize
+	query.Filter = filterPBToFilter(req.Filter)
+	for {
+		ports, nextPageToken, err := s.repo.GetPorts(stream.Context(), query)
+		if err != nil {
+			return statusErr("failed to fetch ports", err)
+		}
+		for _, port := range ports {
+			if err = stream.Send(portToPB(port)); err != nil {
+				return statusErr("failed to send port "+port.ID, err)
+			}
+		}
+		if nextPageToken == "" {
+			return nil
+		}
+		query.PageToken = nextPageToken
+	}
+}
+
+// orderPBToQuery creates query returning ports in given order, ports are ordered by ID
+// when the order isn't set.
+func orderPBToQuery(order *pb2.PortOrder) (domainPort.Query, error) {
+	var query domainPort.Query
+	switch order.GetField() {
+	case pb2.PortOrderField_PORT_ORDER_FIELD_ID:
+		query.OrderBy = domainPort.OrderByID
+	case pb2.PortOrderField_PORT_ORDER_FIELD_NAME:
+		query.OrderBy = domainPort.OrderByName
+	case pb2.PortOrderField_PORT_ORDER_FIELD_COUNTRY:
+		query.OrderBy = domainPort.OrderByCountry
+	case pb2.PortOrderField_PORT_ORDER_FIELD_INSERTION_TIME:
+		query.OrderBy = domainPort.OrderByInsertionTime
+	default:
+		return query, fmt.Errorf("unknown order field %d", order.GetField())
+	}
+	query.Descending = order.GetDescending()
+	return query, nil
+}
+
+func filterPBToFilter(filter *pb2.PortFilter) domainPort.Filter {
+	return domainPort.Filter{
+		Country:  filter.GetCountry(),
+		Province: filter.GetProvince(),
+		City:     filter.GetCity(),
+		Timezone: filter.GetTimezone(),
+		Code:     filter.GetCode(),
+		Region:   filter.GetRegion(),
+		Unloc:    filter.GetUnloc(),
 	}
-	return portToResponsePayload(ports), nil
 }
 
 func portPBToPort(pbPort *pb2.Port) (*domainPort.Port, error) {
+	if pbPort == nil {
+		return nil, &domainPort.ValidationError{
+			Violations: []domainPort.FieldViolation{{Field: "port", Description: "can't be missing"}},
+		}
+	}
+	// location is validated together with the rest of the port, so all violations are reported at once
+	location, locationErr := locationPBToGeoPoint(pbPort)
 	port, err := domainPort.NewPort(
 		pbPort.Id,
 		pbPort.Name,
@@ -58,33 +693,125 @@ func portPBToPort(pbPort *pb2.Port) (*domainPort.Port, error) {
 		pbPort.Country,
 		pbPort.Alias,
 		pbPort.Regions,
-		pbPort.Coordinates,
+		location,
 		pbPort.Province,
 		pbPort.Timezone,
 		pbPort.Unlocs,
 		pbPort.Code)
-	if err != nil {
-		return nil, fmt.Errorf("failed to map pb port to domain port: %w", err)
+	if err = domainPort.JoinValidationErrors(locationErr, err); err != nil {
+		return nil, err
 	}
 	return port, nil
 }
 
-func portToResponsePayload(ports []*domainPort.Port) *pb2.GetPortsResponse {
+// locationPBToGeoPoint reads port location, falling back to legacy coordinates when location
+// isn't set.
+func locationPBToGeoPoint(pbPort *pb2.Port) (*domainPort.GeoPoint, error) {
+	var location *domainPort.GeoPoint
+	if pbPort.Location != nil {
+		location = &domainPort.GeoPoint{Lat: pbPort.Location.Lat, Lon: pbPort.Location.Lon}
+	}
+	return domainPort.ResolveLocation(location, pbPort.Coordinates)
+}
+
+// statusErr converts error into gRPC status with code matching the kind of the error, errors which are
+// statuses already are returned as they are. Violations of port fields are attached to InvalidArgument
+// status as BadRequest details, so clients can tell which fields are invalid.
+func statusErr(message string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domainPort.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domainPort.ErrAlreadyExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, domainPort.ErrInvalid):
+		code = codes.InvalidArgument
+	case errors.Is(err, domainPort.ErrUnavailable):
+		code = codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	st := status.New(code, fmt.Sprintf("%s: %s", message, err.Error()))
+
+	var validationErr *domainPort.ValidationError
+	if !errors.As(err, &validationErr) {
+		return st.Err()
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+	detailedSt, detailsErr := st.WithDetails(badRequest)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailedSt.Err()
+}
+
+func portsToPB(ports []*domainPort.Port) []*pb2.Port {
 	pbPorts := make([]*pb2.Port, len(ports))
 	for i, port := range ports {
-		pbPorts[i] = &pb2.Port{
-			Name:        port.Name,
-			City:        port.City,
-			Country:     port.Country,
-			Alias:       port.Alias,
-			Regions:     port.Regions,
-			Coordinates: port.Coordinates,
-			Province:    port.Province,
-			Timezone:    port.Timezone,
-			Unlocs:      port.Unlocs,
-			Code:        port.Code,
-			Id:          port.ID,
-		}
-	}
-	return &pb2.GetPortsResponse{Ports: pbPorts}
+		pbPorts[i] = portToPB(port)
+	}
+	return pbPorts
+}
+
+func portToPB(port *domainPort.Port) *pb2.Port {
+	return &pb2.Port{
+		Name:        port.Name,
+		City:        port.City,
+		Country:     port.Country,
+		Alias:       port.Alias,
+		Regions:     port.Regions,
+		Coordinates: port.Location.Coordinates(),
+		Province:    port.Province,
+		Timezone:    port.Timezone,
+		Unlocs:      port.Unlocs,
+		Code:        port.Code,
+		Id:          port.ID,
+		Location:    geoPointToPB(port.Location),
+	}
+}
+
+var fieldChangesPB = map[domainPort.FieldChange]pb2.FieldChange{
+	domainPort.FieldAdded:   pb2.FieldChange_FIELD_CHANGE_ADDED,
+	domainPort.FieldRemoved: pb2.FieldChange_FIELD_CHANGE_REMOVED,
+	domainPort.FieldChanged: pb2.FieldChange_FIELD_CHANGE_CHANGED,
+}
+
+// fieldValueToPB converts value of a port field, it returns nil when the field isn't set.
+func fieldValueToPB(value any) *structpb.Value {
+	switch value := value.(type) {
+	case string:
+		return structpb.NewStringValue(value)
+	case []string:
+		values := make([]*structpb.Value, len(value))
+		for i, item := range value {
+			values[i] = structpb.NewStringValue(item)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values})
+	case *domainPort.GeoPoint:
+		return structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+			"lat": structpb.NewNumberValue(value.Lat),
+			"lon": structpb.NewNumberValue(value.Lon),
+		}})
+	default:
+		return nil
+	}
+}
+
+func geoPointToPB(point *domainPort.GeoPoint) *pb2.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &pb2.GeoPoint{Lat: point.Lat, Lon: point.Lon}
 }
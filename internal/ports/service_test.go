@@ -2,15 +2,23 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	pb2 "github.com/arturskrzydlo/ports/internal/common/pb"
 
 	"github.com/arturskrzydlo/ports/internal/ports/adapters"
+	domainPort "github.com/arturskrzydlo/ports/internal/ports/domain/port"
 )
 
 type portsServiceSuite struct {
@@ -46,7 +54,7 @@ func (s *portsServiceSuite) TestStoringPorts() {
 
 		// then
 		s.Require().NoError(err)
-		portsResp, err := s.service.GetPorts(context.Background(), &emptypb.Empty{})
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
 		s.Require().NoError(err)
 		s.Assert().Len(portsResp.Ports, 1)
 		s.Assert().Equal(s.createPbPort(), portsResp.Ports[0])
@@ -64,14 +72,80 @@ func (s *portsServiceSuite) TestStoringPorts() {
 
 		// then
 		s.Require().Error(err)
-		portsResp, err := s.service.GetPorts(context.Background(), &emptypb.Empty{})
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
 		s.Require().NoError(err)
 		s.Assert().Len(portsResp.Ports, 0)
 
 		s.resetStorage()
 	})
 
-	s.Run("should update already existing port", func() {
+	s.Run("should take location from legacy coordinates", func() {
+		// given
+		portToStore := s.createPbPort()
+		portToStore.Location = nil
+
+		// when
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: portToStore})
+
+		// then
+		s.Require().NoError(err)
+		portResp, err := s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: portToStore.Id})
+		s.Require().NoError(err)
+		s.Assert().Equal(s.createPbPort().Location, portResp.Port.Location)
+		s.Assert().Equal(portToStore.Coordinates, portResp.Port.Coordinates)
+
+		s.resetStorage()
+	})
+
+	s.Run("should fail storing a port when its location is invalid", func() {
+		for name, setLocation := range map[string]func(port *pb2.Port){
+			"latitude out of range":  func(port *pb2.Port) { port.Location.Lat = 90.5 },
+			"longitude out of range": func(port *pb2.Port) { port.Location = nil; port.Coordinates = []float64{181, 0} },
+			"single coordinate":      func(port *pb2.Port) { port.Location = nil; port.Coordinates = []float64{90} },
+			"coordinates not matching location": func(port *pb2.Port) {
+				port.Coordinates = []float64{port.Location.Lat, port.Location.Lon}
+			},
+		} {
+			// given
+			portToStore := s.createPbPort()
+			setLocation(portToStore)
+
+			// when
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: portToStore})
+
+			// then
+			s.Require().Error(err, name)
+			portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+			s.Require().NoError(err)
+			s.Assert().Len(portsResp.Ports, 0, name)
+		}
+	})
+
+	s.Run("should report all violations of invalid port as field violations", func() {
+		// given
+		portToStore := s.createPbPort()
+		portToStore.Timezone = "Europe/Atlantis"
+		portToStore.Alias = []string{"alias", "Alias"}
+		portToStore.Location.Lat = 91
+		portToStore.Coordinates = nil
+
+		// when
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: portToStore})
+
+		// then
+		s.Require().Equal(codes.InvalidArgument, status.Code(err))
+		details := status.Convert(err).Details()
+		s.Require().Len(details, 1)
+		badRequest, ok := details[0].(*errdetails.BadRequest)
+		s.Require().True(ok)
+		fields := make([]string, len(badRequest.FieldViolations))
+		for i, violation := range badRequest.FieldViolations {
+			fields[i] = violation.Field
+		}
+		s.Assert().Equal([]string{"timezone", "alias[1]", "location.lat"}, fields)
+	})
+
+	s.Run("shouldn't create already existing port", func() {
 		// given
 		portToStore := s.createPbPort()
 		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: portToStore})
@@ -84,28 +158,1011 @@ func (s *portsServiceSuite) TestStoringPorts() {
 		_, err = s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: updatedPort})
 
 		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.AlreadyExists, status.Code(err))
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
 		s.Require().NoError(err)
-		portsResp, err := s.service.GetPorts(context.Background(), &emptypb.Empty{})
+		s.Assert().Len(portsResp.Ports, 1)
+		s.Assert().Equal(portToStore.Name, portsResp.Ports[0].Name)
+
+		s.resetStorage()
+	})
+}
+
+func (s *portsServiceSuite) TestUpdatingPorts() {
+	s.Run("should update already existing port", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		updatedPort := s.createPbPort()
+		updatedPort.Name = "updated-name"
+
+		// when
+		_, err = s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{Port: updatedPort})
+
+		// then
+		s.Require().NoError(err)
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
 		s.Require().NoError(err)
 		s.Assert().Len(portsResp.Ports, 1)
 		s.Assert().Equal(updatedPort.Name, portsResp.Ports[0].Name)
 
 		s.resetStorage()
 	})
+
+	s.Run("shouldn't update not existing port", func() {
+		// when
+		_, err := s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{Port: s.createPbPort()})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.NotFound, status.Code(err))
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+		s.Require().NoError(err)
+		s.Assert().Len(portsResp.Ports, 0)
+	})
+
+	s.Run("shouldn't update port when it's invalid", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		updatedPort := s.createPbPort()
+		updatedPort.Code = ""
+
+		// when
+		_, err = s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{Port: updatedPort})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.InvalidArgument, status.Code(err))
+
+		s.resetStorage()
+	})
+}
+
+func (s *portsServiceSuite) TestPatchingPorts() {
+	s.Run("should update only fields from update mask", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		patch := &pb2.Port{Id: s.createPbPort().Id, Timezone: "Europe/London"}
+
+		// when
+		_, err = s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{
+			Port:       patch,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"timezone"}},
+		})
+
+		// then
+		s.Require().NoError(err)
+		portResp, err := s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: patch.Id})
+		s.Require().NoError(err)
+		expectedPort := s.createPbPort()
+		expectedPort.Timezone = patch.Timezone
+		s.Assert().Equal(expectedPort, portResp.Port)
+
+		s.resetStorage()
+	})
+
+	s.Run("shouldn't patch unknown field", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		// when
+		_, err = s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{
+			Port:       &pb2.Port{Id: s.createPbPort().Id},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"unknown"}},
+		})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.InvalidArgument, status.Code(err))
+
+		s.resetStorage()
+	})
+
+	s.Run("shouldn't patch not existing port", func() {
+		// when
+		_, err := s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{
+			Port:       &pb2.Port{Id: "unknown"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+		})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+func (s *portsServiceSuite) TestDeletingPorts() {
+	s.Run("should delete existing port", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		// when
+		_, err = s.service.DeletePort(context.Background(), &pb2.DeletePortRequest{Id: s.createPbPort().Id})
+
+		// then
+		s.Require().NoError(err)
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+		s.Require().NoError(err)
+		s.Assert().Len(portsResp.Ports, 0)
+
+		s.resetStorage()
+	})
+
+	s.Run("shouldn't delete not existing port", func() {
+		// when
+		_, err := s.service.DeletePort(context.Background(), &pb2.DeletePortRequest{Id: "unknown"})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+func (s *portsServiceSuite) TestStreamingPorts() {
+	s.Run("should store streamed ports and report created and updated ones", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		updatedPort := s.createPbPort()
+		updatedPort.Name = "updated-name"
+		newPort := s.createPbPort()
+		newPort.Id = "GBSOU"
+		stream := newCreatePortsStream(updatedPort, newPort)
+
+		// when
+		err = s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal(&pb2.IngestSummary{
+			Created:    1,
+			Updated:    1,
+			CreatedIds: []string{"GBSOU"},
+			UpdatedIds: []string{"GBLON"},
+		}, stream.summary)
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+		s.Require().NoError(err)
+		s.Assert().Len(portsResp.Ports, 2)
+
+		s.resetStorage()
+	})
+
+	s.Run("should use default options when stream starts with port", func() {
+		// given
+		stream := newCreatePortsStream(s.createPbPort())
+		stream.options = nil
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal([]string{"GBLON"}, stream.summary.CreatedIds)
+
+		s.resetStorage()
+	})
+
+	s.Run("should reject options which aren't the first message of the stream", func() {
+		// given
+		stream := newCreatePortsStream(s.createPbPort())
+		stream.options = nil
+
+		// when
+		err := s.service.StreamCreatePorts(&lateOptionsStream{
+			createPortsStream: stream,
+			lateOptions:       &pb2.IngestOptions{DryRun: true},
+		})
+
+		// then
+		s.Assert().Equal(codes.InvalidArgument, status.Code(err))
+		s.Assert().Nil(stream.summary)
+
+		s.resetStorage()
+	})
+
+	s.Run("should reject invalid ports without interrupting the stream", func() {
+		// given
+		invalidPort := s.createPbPort()
+		invalidPort.Code = ""
+		stream := newCreatePortsStream(invalidPort, nil, s.createPbPort())
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal(uint32(1), stream.summary.Created)
+		s.Assert().Equal(uint32(2), stream.summary.Rejected)
+		s.Require().Len(stream.summary.Errors, 2)
+		s.Assert().Equal(invalidPort.Id, stream.summary.Errors[0].PortId)
+		s.Assert().NotEmpty(stream.summary.Errors[0].Reason)
+		s.Assert().False(stream.summary.Aborted)
+		s.Assert().Equal(stream.summary.Errors, stream.rejected)
+
+		s.resetStorage()
+	})
+
+	s.Run("should reject ports which client couldn't decode", func() {
+		// given
+		stream := newCreatePortsStream(s.createPbPort(), &pb2.Port{Id: "GBSOU"})
+		stream.entries[1].Offset = 120
+		stream.entries[1].DecodeError = "name must be a string"
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal([]string{"GBLON"}, stream.summary.CreatedIds)
+		s.Assert().Equal([]*pb2.PortError{{PortId: "GBSOU", Reason: "name must be a string", Offset: 120}},
+			stream.summary.Errors)
+
+		s.resetStorage()
+	})
+
+	s.Run("should stop at first rejected port when failing fast", func() {
+		// given
+		invalidPort := s.createPbPort()
+		invalidPort.Id = "GBSOU"
+		invalidPort.Code = ""
+		lastPort := s.createPbPort()
+		lastPort.Id = "PLGDN"
+		stream := newCreatePortsStream(s.createPbPort(), invalidPort, lastPort).
+			withPolicy(pb2.ErrorPolicy_ERROR_POLICY_FAIL_FAST)
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().True(stream.summary.Aborted)
+		s.Assert().Equal([]string{"GBLON"}, stream.summary.CreatedIds)
+		s.Assert().Equal(uint32(1), stream.summary.Rejected)
+		_, err = s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: "PLGDN"})
+		s.Assert().Equal(codes.NotFound, status.Code(err))
+
+		s.resetStorage()
+	})
+
+	s.Run("should store nothing when any port is rejected and all or nothing is required", func() {
+		// given
+		invalidPort := s.createPbPort()
+		invalidPort.Id = "GBSOU"
+		invalidPort.Code = ""
+		stream := newCreatePortsStream(s.createPbPort(), invalidPort).
+			withPolicy(pb2.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING)
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().True(stream.summary.Aborted)
+		s.Assert().Zero(stream.summary.Created)
+		s.Assert().Equal(uint32(1), stream.summary.Rejected)
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+		s.Require().NoError(err)
+		s.Assert().Empty(portsResp.Ports)
+
+		s.resetStorage()
+	})
+
+	s.Run("should leave ports unchanged when atomic stream fails halfway", func() {
+		// given
+		otherPort := s.createPbPort()
+		otherPort.Id = "GBSOU"
+		stream := newCreatePortsStream(s.createPbPort(), otherPort)
+		stream.options.Atomic = true
+		stream.err = status.Error(codes.Canceled, "client canceled the stream")
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Assert().Equal(codes.Canceled, status.Code(err))
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+		s.Require().NoError(err)
+		s.Assert().Empty(portsResp.Ports)
+
+		s.resetStorage()
+	})
+
+	s.Run("should store valid ports of atomic stream once it's received", func() {
+		// given
+		invalidPort := s.createPbPort()
+		invalidPort.Id = "GBSOU"
+		invalidPort.Code = ""
+		stream := newCreatePortsStream(s.createPbPort(), invalidPort)
+		stream.options.Atomic = true
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().False(stream.summary.Aborted)
+		s.Assert().Equal([]string{"GBLON"}, stream.summary.CreatedIds)
+		s.Assert().Equal(uint32(1), stream.summary.Rejected)
+		_, err = s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: "GBLON"})
+		s.Assert().NoError(err)
+
+		s.resetStorage()
+	})
+
+	s.Run("should store all ports when none is rejected and all or nothing is required", func() {
+		// given
+		otherPort := s.createPbPort()
+		otherPort.Id = "GBSOU"
+		stream := newCreatePortsStream(s.createPbPort(), otherPort).
+			withPolicy(pb2.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING)
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().False(stream.summary.Aborted)
+		s.Assert().Equal([]string{"GBLON", "GBSOU"}, stream.summary.CreatedIds)
+
+		s.resetStorage()
+	})
+}
+
+func (s *portsServiceSuite) TestReplacingPorts() {
+	// replacementStream updates GBLON, creates NLRTM and sends GBSOU rejected, as it has no code
+	replacementStream := func() *createPortsStream {
+		updatedPort := s.createPbPort()
+		updatedPort.Name = "updated-name"
+		invalidPort := s.createPbPort()
+		invalidPort.Id = "GBSOU"
+		invalidPort.Code = ""
+		newPort := s.createPbPort()
+		newPort.Id = "NLRTM"
+		stream := newCreatePortsStream(updatedPort, invalidPort, newPort)
+		stream.options.Replace = true
+		return stream
+	}
+
+	s.Run("should delete ports which weren't sent, except rejected ones", func() {
+		// given
+		s.storePorts("GBLON", "GBSOU", "USNYC")
+		stream := replacementStream()
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal([]string{"NLRTM"}, stream.summary.CreatedIds)
+		s.Assert().Equal([]string{"GBLON"}, stream.summary.UpdatedIds)
+		s.Assert().Equal([]string{"USNYC"}, stream.summary.DeletedIds)
+		s.Assert().Equal(uint32(1), stream.summary.Deleted)
+		s.Assert().False(stream.summary.DryRun)
+		s.assertStoredIDs("GBLON", "GBSOU", "NLRTM")
+		nearest, err := s.service.FindNearestPorts(context.Background(),
+			&pb2.FindNearestPortsRequest{Lat: 51.5072, Lon: -0.1275, K: 10})
+		s.Require().NoError(err)
+		s.Assert().Len(nearest.Ports, 3)
+
+		s.resetStorage()
+	})
+
+	s.Run("should only report changes of dry run", func() {
+		// given
+		s.storePorts("GBLON", "GBSOU", "USNYC")
+		stream := replacementStream()
+		stream.options.DryRun = true
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().True(stream.summary.DryRun)
+		s.Assert().Equal([]string{"NLRTM"}, stream.summary.CreatedIds)
+		s.Assert().Equal([]string{"GBLON"}, stream.summary.UpdatedIds)
+		s.Assert().Equal([]string{"USNYC"}, stream.summary.DeletedIds)
+		s.assertStoredIDs("GBLON", "GBSOU", "USNYC")
+		stored, err := s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: "GBLON"})
+		s.Require().NoError(err)
+		s.Assert().Equal("name", stored.Port.Name)
+
+		s.resetStorage()
+	})
+
+	s.Run("should report no deletions of dry run without replacement", func() {
+		// given
+		s.storePorts("GBLON", "USNYC")
+		stream := newCreatePortsStream(s.createPbPort(), s.createPbPort())
+		stream.options.DryRun = true
+
+		// when
+		err := s.service.StreamCreatePorts(stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Empty(stream.summary.CreatedIds)
+		s.Assert().Equal([]string{"GBLON", "GBLON"}, stream.summary.UpdatedIds)
+		s.Assert().Empty(stream.summary.DeletedIds)
+
+		s.resetStorage()
+	})
+}
+
+func (s *portsServiceSuite) TestDiffingPorts() {
+	// given
+	s.storePorts("GBLON", "GBSOU")
+	changedPort := s.createPbPort()
+	changedPort.Name = "changed-name"
+	changedPort.Alias = nil
+	changedPort.Province = ""
+	changedPort.Location = &pb2.GeoPoint{Lat: 51.5, Lon: -0.12}
+	changedPort.Coordinates = nil
+	unchangedPort := s.createPbPort()
+	unchangedPort.Id = "GBSOU"
+	newPort := &pb2.Port{Id: "NLRTM", Code: "some-code", Unlocs: []string{"NLRTM"}}
+	invalidPort := s.createPbPort()
+	invalidPort.Id = "USNYC"
+	invalidPort.Code = ""
+	stream := newDiffPortsStream(changedPort, unchangedPort, newPort, invalidPort)
+	for i, entry := range stream.entries {
+		entry.Offset = int64(i * 100)
+	}
+
+	// when
+	err := s.service.DiffPorts(stream)
+
+	// then
+	s.Require().NoError(err)
+	s.Assert().Equal(uint32(1), stream.diff.New)
+	s.Assert().Equal(uint32(1), stream.diff.Changed)
+	s.Assert().Equal(uint32(1), stream.diff.Unchanged)
+	s.Assert().Equal(uint32(1), stream.diff.Rejected)
+	s.Require().Len(stream.diff.Errors, 1)
+	s.Assert().Equal("USNYC", stream.diff.Errors[0].PortId)
+	s.Assert().Equal(int64(300), stream.diff.Errors[0].Offset)
+
+	s.Require().Len(stream.diff.Ports, 2)
+	changed := stream.diff.Ports[0]
+	s.Assert().Equal("GBLON", changed.PortId)
+	s.Assert().Equal(pb2.PortDiffStatus_PORT_DIFF_STATUS_CHANGED, changed.Status)
+	s.Assert().Zero(changed.Offset)
+	s.Require().Len(changed.Fields, 4)
+	s.Assert().Equal("name", changed.Fields[0].Field)
+	s.Assert().Equal(pb2.FieldChange_FIELD_CHANGE_CHANGED, changed.Fields[0].Change)
+	s.Assert().Equal("name", changed.Fields[0].OldValue.GetStringValue())
+	s.Assert().Equal("changed-name", changed.Fields[0].NewValue.GetStringValue())
+	s.Assert().Equal("alias", changed.Fields[1].Field)
+	s.Assert().Equal(pb2.FieldChange_FIELD_CHANGE_REMOVED, changed.Fields[1].Change)
+	s.Assert().Equal([]any{"alias"}, changed.Fields[1].OldValue.AsInterface())
+	s.Assert().Nil(changed.Fields[1].NewValue)
+	s.Assert().Equal("location", changed.Fields[2].Field)
+	s.Assert().Equal(map[string]any{"lat": 51.5, "lon": -0.12}, changed.Fields[2].NewValue.AsInterface())
+	s.Assert().Equal("province", changed.Fields[3].Field)
+
+	created := stream.diff.Ports[1]
+	s.Assert().Equal("NLRTM", created.PortId)
+	s.Assert().Equal(pb2.PortDiffStatus_PORT_DIFF_STATUS_NEW, created.Status)
+	s.Assert().Equal(int64(200), created.Offset)
+	s.Require().Len(created.Fields, 2)
+	s.Assert().Equal(pb2.FieldChange_FIELD_CHANGE_ADDED, created.Fields[0].Change)
+	s.assertStoredIDs("GBLON", "GBSOU")
+
+	s.resetStorage()
+}
+
+func (s *portsServiceSuite) TestFetchingPorts() {
+	s.Run("should fetch ports page by page ordered by id", func() {
+		// given
+		for _, id := range []string{"PLSZZ", "PLGDN", "PLGDY"} {
+			port := s.createPbPort()
+			port.Id = id
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+			s.Require().NoError(err)
+		}
+
+		// when
+		firstPage, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{PageSize: 2})
+		s.Require().NoError(err)
+		secondPage, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{
+			PageSize:  2,
+			PageToken: firstPage.NextPageToken,
+		})
+		s.Require().NoError(err)
+
+		// then
+		s.Require().Len(firstPage.Ports, 2)
+		s.Assert().Equal("PLGDN", firstPage.Ports[0].Id)
+		s.Assert().Equal("PLGDY", firstPage.Ports[1].Id)
+		s.Assert().NotEmpty(firstPage.NextPageToken)
+		s.Require().Len(secondPage.Ports, 1)
+		s.Assert().Equal("PLSZZ", secondPage.Ports[0].Id)
+		s.Assert().Empty(secondPage.NextPageToken)
+
+		s.resetStorage()
+	})
+
+	s.Run("should fetch ports in requested order", func() {
+		// given
+		for id, name := range map[string]string{"PLGDN": "Gdansk", "AEAJM": "Ajman", "GBLON": "London"} {
+			port := s.createPbPort()
+			port.Id = id
+			port.Name = name
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+			s.Require().NoError(err)
+		}
+		order := &pb2.PortOrder{Field: pb2.PortOrderField_PORT_ORDER_FIELD_NAME, Descending: true}
+
+		// when
+		portsResp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{Order: order})
+		s.Require().NoError(err)
+		stream := &portsStream{}
+		streamErr := s.service.StreamPorts(&pb2.StreamPortsRequest{Order: order}, stream)
+
+		// then
+		s.Require().NoError(streamErr)
+		for _, ports := range [][]*pb2.Port{portsResp.Ports, stream.ports} {
+			s.Require().Len(ports, 3)
+			s.Assert().Equal("London", ports[0].Name)
+			s.Assert().Equal("Gdansk", ports[1].Name)
+			s.Assert().Equal("Ajman", ports[2].Name)
+		}
+
+		s.resetStorage()
+	})
+
+	s.Run("should list only ports matching the filter", func() {
+		// given
+		for id, country := range map[string]string{"PLGDN": "Poland", "PLGDY": "United Kingdom", "PLSZZ": "Poland"} {
+			port := s.createPbPort()
+			port.Id = id
+			port.Country = country
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+			s.Require().NoError(err)
+		}
+		filter := &pb2.PortFilter{Country: "poland"}
+
+		// when
+		firstPage, err := s.service.ListPorts(context.Background(), &pb2.ListPortsRequest{PageSize: 1, Filter: filter})
+		s.Require().NoError(err)
+		secondPage, err := s.service.ListPorts(context.Background(), &pb2.ListPortsRequest{
+			PageSize:  1,
+			PageToken: firstPage.NextPageToken,
+			Filter:    filter,
+		})
+		s.Require().NoError(err)
+		stream := &portsStream{}
+		streamErr := s.service.StreamPorts(&pb2.StreamPortsRequest{Filter: filter}, stream)
+
+		// then
+		s.Require().Len(firstPage.Ports, 1)
+		s.Assert().Equal("PLGDN", firstPage.Ports[0].Id)
+		s.Require().Len(secondPage.Ports, 1)
+		s.Assert().Equal("PLSZZ", secondPage.Ports[0].Id)
+		s.Assert().Empty(secondPage.NextPageToken)
+		s.Require().NoError(streamErr)
+		s.Assert().Len(stream.ports, 2)
+
+		s.resetStorage()
+	})
+
+	s.Run("should reject unknown order field", func() {
+		// when
+		_, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{
+			Order: &pb2.PortOrder{Field: pb2.PortOrderField(100)},
+		})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	s.Run("should fetch single port by id", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+
+		// when
+		portResp, err := s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: s.createPbPort().Id})
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Equal(s.createPbPort(), portResp.Port)
+
+		s.resetStorage()
+	})
+
+	s.Run("should return not found for unknown port", func() {
+		// when
+		_, err := s.service.GetPort(context.Background(), &pb2.GetPortRequest{Id: "unknown"})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+
+	s.Run("should fail fetching ports with invalid page token", func() {
+		// when
+		_, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{PageToken: "%%%"})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	s.Run("should stream all ports", func() {
+		// given
+		for i := 0; i < streamBatchSize+1; i++ {
+			port := s.createPbPort()
+			port.Id = testUNLocode(i)
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+			s.Require().NoError(err)
+		}
+		stream := &portsStream{}
+
+		// when
+		err := s.service.StreamPorts(&pb2.StreamPortsRequest{}, stream)
+
+		// then
+		s.Require().NoError(err)
+		s.Assert().Len(stream.ports, streamBatchSize+1)
+
+		s.resetStorage()
+	})
+}
+
+func (s *portsServiceSuite) TestSearchingPorts() {
+	s.Run("should find ports by name, city and alias", func() {
+		// given
+		for id, name := range map[string]string{"PLGDN": "Gdańsk", "PLGDY": "Gdynia", "PLSZZ": "London"} {
+			port := s.createPbPort()
+			port.Id = id
+			port.Name = name
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+			s.Require().NoError(err)
+		}
+
+		// when
+		prefixResp, err := s.service.SearchPorts(context.Background(), &pb2.SearchPortsRequest{Query: "gd"})
+		s.Require().NoError(err)
+		typoResp, err := s.service.SearchPorts(context.Background(), &pb2.SearchPortsRequest{Query: "gdnask", Limit: 1})
+		s.Require().NoError(err)
+
+		// then
+		s.Assert().Len(prefixResp.Ports, 2)
+		s.Require().Len(typoResp.Ports, 1)
+		s.Assert().Equal("PLGDN", typoResp.Ports[0].Id)
+
+		s.resetStorage()
+	})
+
+	s.Run("should keep search index in sync with stored ports", func() {
+		// given
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: s.createPbPort()})
+		s.Require().NoError(err)
+		renamedPort := s.createPbPort()
+		renamedPort.Name = "Ajman"
+		streamedPort := s.createPbPort()
+		streamedPort.Id = "GBSOU"
+		streamedPort.Name = "Abu Dhabi"
+
+		// when
+		_, err = s.service.UpdatePort(context.Background(), &pb2.UpdatePortRequest{Port: renamedPort})
+		s.Require().NoError(err)
+		s.Require().NoError(s.service.StreamCreatePorts(newCreatePortsStream(streamedPort)))
+		_, err = s.service.DeletePort(context.Background(), &pb2.DeletePortRequest{Id: renamedPort.Id})
+		s.Require().NoError(err)
+
+		// then
+		resp, err := s.service.SearchPorts(context.Background(), &pb2.SearchPortsRequest{Query: "ajman"})
+		s.Require().NoError(err)
+		s.Assert().Empty(resp.Ports)
+		resp, err = s.service.SearchPorts(context.Background(), &pb2.SearchPortsRequest{Query: "abu"})
+		s.Require().NoError(err)
+		s.Require().Len(resp.Ports, 1)
+		s.Assert().Equal("GBSOU", resp.Ports[0].Id)
+
+		s.resetStorage()
+	})
+
+	s.Run("should index ports already stored in repository", func() {
+		// given
+		port := s.createPbPort()
+		port.Name = "Ajman"
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+		s.Require().NoError(err)
+		service := NewPortsService(zap.NewNop(), s.repo)
+
+		// when
+		err = service.LoadIndexes(context.Background())
+
+		// then
+		s.Require().NoError(err)
+		resp, err := service.SearchPorts(context.Background(), &pb2.SearchPortsRequest{Query: "ajman"})
+		s.Require().NoError(err)
+		s.Assert().Len(resp.Ports, 1)
+
+		s.resetStorage()
+	})
+
+	s.Run("should reject query without words", func() {
+		// when
+		_, err := s.service.SearchPorts(context.Background(), &pb2.SearchPortsRequest{Query: " ,"})
+
+		// then
+		s.Require().Error(err)
+		s.Assert().Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (s *portsServiceSuite) TestFindingPortsByLocation() {
+	s.Run("should find nearest ports and ports in bounding box", func() {
+		// given
+		for id, coordinates := range map[string][]float64{
+			"AEAJM": {55.5136433, 25.4052165},
+			"AEAUH": {54.37, 24.47},
+			"GBLON": {-0.1, 51.5},
+		} {
+			port := s.createPbPort()
+			port.Id = id
+			port.Coordinates = coordinates
+			port.Location = nil
+			_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+			s.Require().NoError(err)
+		}
+		// port without location isn't indexed
+		unlocatedPort := s.createPbPort()
+		unlocatedPort.Id = "GBSOU"
+		unlocatedPort.Coordinates = nil
+		unlocatedPort.Location = nil
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: unlocatedPort})
+		s.Require().NoError(err)
+
+		// when
+		nearestResp, err := s.service.FindNearestPorts(context.Background(), &pb2.FindNearestPortsRequest{
+			Lat: 25.4, Lon: 55.5, MaxDistanceKm: 500,
+		})
+		s.Require().NoError(err)
+		boxResp, err := s.service.FindPortsInBoundingBox(context.Background(), &pb2.FindPortsInBoundingBoxRequest{
+			MinLat: 50, MinLon: -10, MaxLat: 60, MaxLon: 10,
+		})
+		s.Require().NoError(err)
+
+		// then
+		s.Require().Len(nearestResp.Ports, 2)
+		s.Assert().Equal("AEAJM", nearestResp.Ports[0].Port.Id)
+		s.Assert().Less(nearestResp.Ports[0].DistanceKm, 5.0)
+		s.Assert().Equal("AEAUH", nearestResp.Ports[1].Port.Id)
+		s.Require().Len(boxResp.Ports, 1)
+		s.Assert().Equal("GBLON", boxResp.Ports[0].Id)
+
+		s.resetStorage()
+	})
+
+	s.Run("should reject invalid location", func() {
+		// when
+		_, nearestErr := s.service.FindNearestPorts(context.Background(), &pb2.FindNearestPortsRequest{Lat: 91})
+		_, boxErr := s.service.FindPortsInBoundingBox(context.Background(), &pb2.FindPortsInBoundingBoxRequest{
+			MinLat: 10, MaxLat: -10,
+		})
+
+		// then
+		s.Assert().Equal(codes.InvalidArgument, status.Code(nearestErr))
+		s.Assert().Equal(codes.InvalidArgument, status.Code(boxErr))
+	})
+}
+
+func (s *portsServiceSuite) TestMappingErrorsToStatusCodes() {
+	for err, code := range map[error]codes.Code{
+		domainPort.ErrNotFound:                                       codes.NotFound,
+		domainPort.ErrAlreadyExists:                                  codes.AlreadyExists,
+		domainPort.ErrInvalidPageToken:                               codes.InvalidArgument,
+		fmt.Errorf("failed to query: %w", domainPort.ErrUnavailable): codes.Unavailable,
+		context.DeadlineExceeded:                                     codes.DeadlineExceeded,
+		errors.New("unexpected"):                                     codes.Internal,
+	} {
+		s.Run("should map "+err.Error(), func() {
+			// given
+			service := NewPortsService(zap.NewNop(), &failingRepo{err: err})
+
+			// when
+			_, getErr := service.GetPort(context.Background(), &pb2.GetPortRequest{Id: "GBLON"})
+			_, listErr := service.ListPorts(context.Background(), &pb2.ListPortsRequest{})
+			_, deleteErr := service.DeletePort(context.Background(), &pb2.DeletePortRequest{Id: "GBLON"})
+
+			// then
+			s.Assert().Equal(code, status.Code(getErr))
+			s.Assert().Equal(code, status.Code(listErr))
+			s.Assert().Equal(code, status.Code(deleteErr))
+		})
+	}
+}
+
+// failingRepo fails every call with the same error.
+type failingRepo struct {
+	Repository
+	err error
+}
+
+func (f *failingRepo) GetPort(context.Context, string) (*domainPort.Port, error) {
+	return nil, f.err
+}
+
+func (f *failingRepo) GetPorts(context.Context, domainPort.Query) ([]*domainPort.Port, string, error) {
+	return nil, "", f.err
+}
+
+func (f *failingRepo) DeletePort(context.Context, string) error {
+	return f.err
+}
+
+type portsStream struct {
+	grpc.ServerStream
+	ports []*pb2.Port
+}
+
+func (p *portsStream) Context() context.Context {
+	return context.Background()
+}
+
+func (p *portsStream) Send(port *pb2.Port) error {
+	p.ports = append(p.ports, port)
+	return nil
+}
+
+type createPortsStream struct {
+	grpc.ServerStream
+	// options are sent as the first request of the stream unless they're nil
+	options *pb2.IngestOptions
+	entries []*pb2.PortEntry
+	// err is returned once all requests are received, io.EOF is returned when it's nil
+	err     error
+	summary *pb2.IngestSummary
+	// rejected are ports reported before the summary
+	rejected []*pb2.PortError
+}
+
+func newCreatePortsStream(ports ...*pb2.Port) *createPortsStream {
+	entries := make([]*pb2.PortEntry, len(ports))
+	for i, port := range ports {
+		entries[i] = &pb2.PortEntry{Port: port}
+	}
+	return &createPortsStream{options: &pb2.IngestOptions{}, entries: entries}
+}
+
+// withPolicy sets error policy in options of the stream.
+func (c *createPortsStream) withPolicy(policy pb2.ErrorPolicy) *createPortsStream {
+	c.options.ErrorPolicy = policy
+	return c
+}
+
+func (c *createPortsStream) Context() context.Context {
+	return context.Background()
+}
+
+func (c *createPortsStream) Recv() (*pb2.StreamCreatePortsRequest, error) {
+	if c.options != nil {
+		req := &pb2.StreamCreatePortsRequest{Request: &pb2.StreamCreatePortsRequest_Options{Options: c.options}}
+		c.options = nil
+		return req, nil
+	}
+	entry, err := c.nextEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &pb2.StreamCreatePortsRequest{Request: &pb2.StreamCreatePortsRequest_Entry{Entry: entry}}, nil
+}
+
+func (c *createPortsStream) nextEntry() (*pb2.PortEntry, error) {
+	if len(c.entries) == 0 && c.err != nil {
+		return nil, c.err
+	}
+	if len(c.entries) == 0 {
+		return nil, io.EOF
+	}
+	entry := c.entries[0]
+	c.entries = c.entries[1:]
+	return entry, nil
+}
+
+func (c *createPortsStream) Send(resp *pb2.StreamCreatePortsResponse) error {
+	if summary := resp.GetSummary(); summary != nil {
+		c.summary = summary
+		return nil
+	}
+	c.rejected = append(c.rejected, resp.GetRejected())
+	return nil
+}
+
+// lateOptionsStream sends options after all entries of createPortsStream.
+type lateOptionsStream struct {
+	*createPortsStream
+	lateOptions *pb2.IngestOptions
+}
+
+func (l *lateOptionsStream) Recv() (*pb2.StreamCreatePortsRequest, error) {
+	req, err := l.createPortsStream.Recv()
+	if errors.Is(err, io.EOF) && l.lateOptions != nil {
+		req, err = &pb2.StreamCreatePortsRequest{Request: &pb2.StreamCreatePortsRequest_Options{Options: l.lateOptions}}, nil
+		l.lateOptions = nil
+	}
+	return req, err
+}
+
+// diffPortsStream receives the same entries as createPortsStream without options, but it's closed with a diff.
+type diffPortsStream struct {
+	*createPortsStream
+	diff *pb2.PortsDiff
+}
+
+func newDiffPortsStream(ports ...*pb2.Port) *diffPortsStream {
+	stream := newCreatePortsStream(ports...)
+	stream.options = nil
+	return &diffPortsStream{createPortsStream: stream}
+}
+
+func (d *diffPortsStream) Recv() (*pb2.PortEntry, error) {
+	return d.nextEntry()
+}
+
+func (d *diffPortsStream) SendAndClose(diff *pb2.PortsDiff) error {
+	d.diff = diff
+	return nil
+}
+
+// testUNLocode returns distinct UN/LOCODE for every number.
+func testUNLocode(number int) string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ23456789"
+	code := []byte("XX")
+	for i := 0; i < 3; i++ {
+		code = append(code, alphabet[number%len(alphabet)])
+		number /= len(alphabet)
+	}
+	return string(code)
+}
+
+// storePorts stores valid ports with the given IDs.
+func (s *portsServiceSuite) storePorts(ids ...string) {
+	for _, id := range ids {
+		port := s.createPbPort()
+		port.Id = id
+		_, err := s.service.CreatePort(context.Background(), &pb2.CreatePortRequest{Port: port})
+		s.Require().NoError(err)
+	}
+}
+
+func (s *portsServiceSuite) assertStoredIDs(expected ...string) {
+	resp, err := s.service.GetPorts(context.Background(), &pb2.GetPortsRequest{})
+	s.Require().NoError(err)
+	ids := make([]string, 0, len(resp.Ports))
+	for _, port := range resp.Ports {
+		ids = append(ids, port.Id)
+	}
+	s.Assert().Equal(expected, ids)
 }
 
 func (s *portsServiceSuite) createPbPort() *pb2.Port {
 	return &pb2.Port{
 		Name:        "name",
 		City:        "London",
-		Country:     "UK",
+		Country:     "United Kingdom",
 		Alias:       []string{"alias"},
 		Regions:     []string{"regions"},
-		Coordinates: []float64{90.0},
+		Coordinates: []float64{-0.1275, 51.5072},
 		Province:    "province",
 		Timezone:    "UTC",
-		Unlocs:      []string{"unloc"},
+		Unlocs:      []string{"GBLON"},
 		Code:        "some-code",
-		Id:          "some-id",
+		Id:          "GBLON",
+		Location:    &pb2.GeoPoint{Lat: 51.5072, Lon: -0.1275},
 	}
 }
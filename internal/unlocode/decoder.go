@@ -0,0 +1,265 @@
+// Package unlocode reads ports from the code list of UN/LOCODE distributed by UNECE as csv files and imports
+// them into ports service. It's used by clients of ports service, so ports are read into its own Port, which
+// mirrors fields of ports.
+package unlocode
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arturskrzydlo/ports/internal/common/countries"
+)
+
+// columns of the code list, which has no header row
+const (
+	changeColumn = iota
+	countryColumn
+	locationColumn
+	nameColumn
+	nameWithoutDiacriticsColumn
+	subdivisionColumn
+	functionColumn
+	statusColumn
+	dateColumn
+	iataColumn
+	coordinatesColumn
+	// remarks are the last column, which some editions omit
+	columnsCount = coordinatesColumn + 1
+)
+
+const (
+	// markedForDeletion is change indicator of entries removed from the code list
+	markedForDeletion = "X"
+	// referenceEntry is change indicator of entries which only point at another name of a location
+	referenceEntry = "="
+	// toBeRemoved is status of entries which will be removed from the next issue of the code list
+	toBeRemoved = "XX"
+	// portFunction is set at the first position of function classifier of ports, i.e. "1-3-----"
+	portFunction = '1'
+)
+
+// coordinatesPattern matches coordinates in degrees and minutes, i.e. "2524N 05530E"
+var coordinatesPattern = regexp.MustCompile(`^(\d{2})(\d{2})([NS]) (\d{3})(\d{2})([EW])$`)
+
+// Port is a port read from the code list, its fields are named after fields of ports.
+type Port struct {
+	ID       string
+	Name     string
+	City     string
+	Country  string
+	Alias    []string
+	Location *Location
+	Province string
+	Unlocs   []string
+	Code     string
+}
+
+// Location is a location of the port in degrees, latitude within [-90, 90] and longitude within [-180, 180].
+type Location struct {
+	Lat float64
+	Lon float64
+}
+
+// Entry is a port read from the code list along with byte offset of its row.
+type Entry struct {
+	Port   *Port
+	Offset int64
+	// Err is set when the row is a port, but it can't be mapped onto it, i.e. its coordinates are malformed.
+	// Port has only ID set then.
+	Err error
+}
+
+// RowError stops decoding of the code list at the row which isn't UN/LOCODE entry, Offset is byte offset
+// of the row.
+type RowError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row at offset %d %v", e.Offset, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads ports from the code list. Rows of other locations, i.e. airports or rail terminals,
+// rows of countries, reference entries and entries which are removed from the code list are skipped.
+type Decoder struct {
+	reader       *csv.Reader
+	subdivisions map[string]string
+}
+
+// NewDecoder creates a decoder of the code list, i.e. "2023-1 UNLOCODE CodeListPart1.csv". Province of ports
+// is a name from subdivisions, keyed by ISO 3166-2 codes (see ReadSubdivisions), or the code itself when the
+// subdivision isn't there. Subdivisions may be nil.
+func NewDecoder(r io.Reader, subdivisions map[string]string) *Decoder {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+	return &Decoder{reader: reader, subdivisions: subdivisions}
+}
+
+// Next returns the next port of the code list or io.EOF when there are no more of them. Rows which can't be
+// read are returned as RowError.
+func (d *Decoder) Next() (*Entry, error) {
+	for {
+		offset := d.reader.InputOffset()
+		record, err := d.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, &RowError{Offset: offset, Err: fmt.Errorf("can't be read: %w", err)}
+		}
+		if len(record) < columnsCount {
+			return nil, &RowError{Offset: offset, Err: fmt.Errorf(
+				"isn't UN/LOCODE entry, it has %d columns instead of at least %d", len(record), columnsCount)}
+		}
+		for i := range record {
+			record[i] = decodeLatin1(record[i])
+		}
+		if !isPort(record) {
+			continue
+		}
+		entry := &Entry{Offset: offset}
+		entry.Port, entry.Err = d.port(record)
+		return entry, nil
+	}
+}
+
+// InputOffset returns byte offset of the end of the last read row, which is the size of the code list
+// once Next returns io.EOF.
+func (d *Decoder) InputOffset() int64 {
+	return d.reader.InputOffset()
+}
+
+// isPort tells whether the row is a port which is still in the code list.
+func isPort(record []string) bool {
+	change := strings.TrimSpace(record[changeColumn])
+	return record[locationColumn] != "" &&
+		change != markedForDeletion && change != referenceEntry &&
+		record[statusColumn] != toBeRemoved &&
+		strings.IndexByte(record[functionColumn], portFunction) == 0
+}
+
+func (d *Decoder) port(record []string) (*Port, error) {
+	countryCode := strings.TrimSpace(record[countryColumn])
+	id := countryCode + strings.TrimSpace(record[locationColumn])
+	location, err := parseCoordinates(strings.TrimSpace(record[coordinatesColumn]))
+	if err != nil {
+		return &Port{ID: id}, err
+	}
+
+	name := strings.TrimSpace(record[nameColumn])
+	var alias []string
+	if plainName := strings.TrimSpace(record[nameWithoutDiacriticsColumn]); plainName != "" && plainName != name {
+		alias = []string{plainName}
+	}
+	country, ok := countries.Name(countryCode)
+	if !ok {
+		country = countryCode
+	}
+	var province string
+	if subdivision := strings.TrimSpace(record[subdivisionColumn]); subdivision != "" {
+		code := countryCode + "-" + subdivision
+		if province, ok = d.subdivisions[code]; !ok {
+			province = code
+		}
+	}
+
+	return &Port{
+		ID:       id,
+		Name:     name,
+		City:     name,
+		Country:  country,
+		Alias:    alias,
+		Location: location,
+		Province: province,
+		Unlocs:   []string{id},
+		// the code list has no code of its own, whereas ports can't be stored without it
+		Code: id,
+	}, nil
+}
+
+// parseCoordinates reads coordinates in degrees and minutes, i.e. "2524N 05530E", which are rounded to
+// 4 decimal places of degree. It returns nil when there are no coordinates.
+func parseCoordinates(coordinates string) (*Location, error) {
+	if coordinates == "" {
+		return nil, nil
+	}
+	match := coordinatesPattern.FindStringSubmatch(coordinates)
+	if match == nil {
+		return nil, fmt.Errorf("coordinates must be given in degrees and minutes, i.e. 2524N 05530E, got %q",
+			coordinates)
+	}
+	lat, latErr := degrees(match[1], match[2], match[3] == "S", 90)
+	lon, lonErr := degrees(match[4], match[5], match[6] == "W", 180)
+	if err := errors.Join(latErr, lonErr); err != nil {
+		return nil, fmt.Errorf("invalid coordinates %q: %w", coordinates, err)
+	}
+	return &Location{Lat: lat, Lon: lon}, nil
+}
+
+// degrees returns the value of degrees and minutes, which can't exceed maxDegrees.
+func degrees(degrees, minutes string, negative bool, maxDegrees float64) (float64, error) {
+	// both of them are known to be digits
+	wholeDegrees, _ := strconv.Atoi(degrees)
+	wholeMinutes, _ := strconv.Atoi(minutes)
+	if wholeMinutes >= 60 {
+		return 0, fmt.Errorf("minutes must be less than 60, got %d", wholeMinutes)
+	}
+	value := math.Round((float64(wholeDegrees)+float64(wholeMinutes)/60)*1e4) / 1e4
+	if value > maxDegrees {
+		return 0, fmt.Errorf("degrees must be at most %v, got %v", maxDegrees, value)
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// ReadSubdivisions reads names of subdivisions from subdivision codes of UN/LOCODE, i.e.
+// "2023-1 SubdivisionCodes.csv", keyed by their ISO 3166-2 codes, i.e. "AE-AJ".
+func ReadSubdivisions(r io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+	subdivisions := make(map[string]string)
+	for {
+		offset := reader.InputOffset()
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return subdivisions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read subdivision at offset %d: %w", offset, err)
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("row at offset %d isn't a subdivision, it has %d columns instead of at least 3",
+				offset, len(record))
+		}
+		code := strings.TrimSpace(record[0]) + "-" + strings.TrimSpace(record[1])
+		subdivisions[code] = strings.TrimSpace(decodeLatin1(record[2]))
+	}
+}
+
+// decodeLatin1 converts the value to UTF-8 unless it already is, since editions of the code list are
+// encoded either in UTF-8 or in ISO 8859-1.
+func decodeLatin1(value string) string {
+	if utf8.ValidString(value) {
+		return value
+	}
+	runes := make([]rune, len(value))
+	for i := 0; i < len(value); i++ {
+		runes[i] = rune(value[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,149 @@
+package unlocode
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodingCodeList(t *testing.T) {
+	tests := map[string]struct {
+		rows     string
+		expected []*Entry
+	}{
+		"should map port onto its fields": {
+			rows: `"+","AE","AUH","Abu Zaby (Abu Dhabi)","Abu Zaby (Abu Dhabi)","AZ","1-345---","AI","0601","","2428N 05422E",""` + "\n",
+			expected: []*Entry{{Port: &Port{
+				ID:       "AEAUH",
+				Name:     "Abu Zaby (Abu Dhabi)",
+				City:     "Abu Zaby (Abu Dhabi)",
+				Country:  "United Arab Emirates",
+				Location: &Location{Lat: 24.4667, Lon: 54.3667},
+				Province: "Abu Zaby",
+				Unlocs:   []string{"AEAUH"},
+				Code:     "AEAUH",
+			}}},
+		},
+		"should keep name without diacritics as alias and subdivision code without its name": {
+			rows: `,"PL","GDN","Gdańsk","Gdansk","22","1234----","AI","9501",,"5421N 01839E",` + "\n",
+			expected: []*Entry{{Port: &Port{
+				ID:       "PLGDN",
+				Name:     "Gdańsk",
+				City:     "Gdańsk",
+				Country:  "Poland",
+				Alias:    []string{"Gdansk"},
+				Location: &Location{Lat: 54.35, Lon: 18.65},
+				Province: "PL-22",
+				Unlocs:   []string{"PLGDN"},
+				Code:     "PLGDN",
+			}}},
+		},
+		"should decode ISO 8859-1 names and negate southern and western coordinates": {
+			rows: "\"|\",\"BR\",\"SSZ\",\"Santos\",\"Santos\",\"\",\"1-3-----\",\"AI\",\"0307\",\"\",\"2357S 04619W\",\"\"\n" +
+				"\"#\",\"PE\",\"CLL\",\"Callao\xed\",\"Callaoi\",\"\",\"1-------\",\"AI\",\"0307\",\"\",\"\",\"\"\n",
+			expected: []*Entry{
+				{Port: &Port{
+					ID:       "BRSSZ",
+					Name:     "Santos",
+					City:     "Santos",
+					Country:  "Brazil",
+					Location: &Location{Lat: -23.95, Lon: -46.3167},
+					Unlocs:   []string{"BRSSZ"},
+					Code:     "BRSSZ",
+				}},
+				{Offset: 80, Port: &Port{
+					ID:      "PECLL",
+					Name:    "Callaoí",
+					City:    "Callaoí",
+					Country: "Peru",
+					Alias:   []string{"Callaoi"},
+					Unlocs:  []string{"PECLL"},
+					Code:    "PECLL",
+				}},
+			},
+		},
+		"should skip countries, references, removed entries and locations which aren't ports": {
+			rows: `,"AE",,".UNITED ARAB EMIRATES",,,,,,,,` + "\n" +
+				`"=","AE",,"Abu Dhabi = Abu Zaby",,,,,,,,` + "\n" +
+				`"X","AE","XXX","Removed","Removed","","1-------","AI","0601","","",""` + "\n" +
+				`,"AE","YYY","Obsolete","Obsolete","","1-------","XX","0601","","",""` + "\n" +
+				`,"AE","DXB","Dubai","Dubai","DU","-2345---","AI","0601","","",""` + "\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			decoder := NewDecoder(strings.NewReader(tc.rows), map[string]string{"AE-AZ": "Abu Zaby"})
+
+			// when
+			var entries []*Entry
+			for {
+				entry, err := decoder.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				entries = append(entries, entry)
+			}
+
+			// then
+			assert.Equal(t, tc.expected, entries)
+		})
+	}
+
+	t.Run("should report malformed coordinates of port", func(t *testing.T) {
+		// given
+		row := `,"AE","AJM","Ajman","Ajman","AJ","1-------","AI","0601","","2524N 05575E",""` + "\n"
+		decoder := NewDecoder(strings.NewReader(row), nil)
+
+		// when
+		entry, err := decoder.Next()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Port{ID: "AEAJM"}, entry.Port)
+		assert.EqualError(t, entry.Err, `invalid coordinates "2524N 05575E": minutes must be less than 60, got 75`)
+	})
+
+	t.Run("should report coordinates out of range of port", func(t *testing.T) {
+		// given
+		row := `,"AE","AJM","Ajman","Ajman","AJ","1-------","AI","0601","","9530N 05530E",""` + "\n"
+		decoder := NewDecoder(strings.NewReader(row), nil)
+
+		// when
+		entry, err := decoder.Next()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Port{ID: "AEAJM"}, entry.Port)
+		assert.EqualError(t, entry.Err, `invalid coordinates "9530N 05530E": degrees must be at most 90, got 95.5`)
+	})
+
+	t.Run("should fail on rows which aren't UN/LOCODE entries", func(t *testing.T) {
+		// given
+		decoder := NewDecoder(strings.NewReader("id,name\nAEAJM,Ajman\n"), nil)
+
+		// when
+		_, err := decoder.Next()
+
+		// then
+		assert.EqualError(t, err, "row at offset 0 isn't UN/LOCODE entry, it has 2 columns instead of at least 11")
+	})
+}
+
+func TestReadingSubdivisions(t *testing.T) {
+	// given
+	rows := `"AE","AJ","'Ajman","Emirate"` + "\n" + "\"AT\",\"9\",\"Wien\xa0\",\"State\"\n"
+
+	// when
+	subdivisions, err := ReadSubdivisions(strings.NewReader(rows))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"AE-AJ": "'Ajman", "AT-9": "Wien"}, subdivisions)
+}
@@ -0,0 +1,176 @@
+package unlocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/arturskrzydlo/ports/internal/common/pb"
+)
+
+// abortError stops ingestion at the row which isn't UN/LOCODE entry, offset is counted from the beginning
+// of the first file.
+type abortError struct {
+	offset int64
+	err    error
+}
+
+func (e *abortError) Error() string {
+	return e.err.Error()
+}
+
+func (e *abortError) Unwrap() error {
+	return e.err
+}
+
+type summaryResult struct {
+	summary *pb.IngestSummary
+	err     error
+}
+
+// Import sends ports of the files to ports service as a single ingestion, so all parts of the code list can
+// replace stored ports together. Offsets of ports are counted from the beginning of the first file, as if the
+// files were concatenated. A row which isn't UN/LOCODE entry aborts the ingestion: non-atomic stream is closed,
+// so ports stored before are listed in the summary, whereas atomic one is canceled, which leaves stored ports
+// unchanged. Either way the summary is aborted and the row, named by its file, is its last rejected entry.
+func Import(ctx context.Context, client pb.PortServiceClient, opts *pb.IngestOptions, files []*os.File,
+	subdivisions map[string]string,
+) (*pb.IngestSummary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.StreamCreatePorts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ports stream to ports service: %w", err)
+	}
+	// rejected ports are received while ports are sent, so ports service isn't blocked on reporting them
+	summaries := make(chan summaryResult, 1)
+	go func() {
+		summary, err := receiveSummary(stream)
+		summaries <- summaryResult{summary: summary, err: err}
+	}()
+
+	err = stream.Send(&pb.StreamCreatePortsRequest{Request: &pb.StreamCreatePortsRequest_Options{Options: opts}})
+	if err == nil {
+		err = sendFiles(stream, files, subdivisions)
+	}
+	var abortErr *abortError
+	switch {
+	case errors.Is(err, io.EOF):
+		// stream is closed by ports service, its status is received instead of the summary
+	case errors.As(err, &abortErr):
+		return abort(stream, summaries, opts, abortErr)
+	case err != nil:
+		return nil, fmt.Errorf("failed to send ports to ports service: %w", err)
+	}
+	return closeAndRecv(stream, summaries)
+}
+
+// sendFiles sends ports of the files one after another. Rows which aren't UN/LOCODE entries are returned
+// as abortError, io.EOF is returned when the stream is closed by ports service.
+func sendFiles(stream pb.PortService_StreamCreatePortsClient, files []*os.File, subdivisions map[string]string,
+) error {
+	// start is the offset of the beginning of the file
+	var start int64
+	for _, file := range files {
+		decoder := NewDecoder(file, subdivisions)
+		for {
+			entry, err := decoder.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				offset := decoder.InputOffset()
+				var rowErr *RowError
+				if errors.As(err, &rowErr) {
+					offset = rowErr.Offset
+				}
+				return &abortError{offset: start + offset, err: fmt.Errorf("%s: %w", file.Name(), err)}
+			}
+
+			portEntry := &pb.PortEntry{Port: portToPB(entry.Port), Offset: start + entry.Offset}
+			if entry.Err != nil {
+				portEntry.Port = &pb.Port{Id: entry.Port.ID}
+				portEntry.DecodeError = fmt.Sprintf("failed to decode port: %v", entry.Err)
+			}
+			err = stream.Send(&pb.StreamCreatePortsRequest{Request: &pb.StreamCreatePortsRequest_Entry{Entry: portEntry}})
+			if err != nil {
+				return err
+			}
+		}
+		start += decoder.InputOffset()
+	}
+	return nil
+}
+
+// receiveSummary receives responses of ports service until the summary, which lists rejected ports as well.
+func receiveSummary(stream pb.PortService_StreamCreatePortsClient) (*pb.IngestSummary, error) {
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("stream closed without summary: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if summary := resp.GetSummary(); summary != nil {
+			return summary, nil
+		}
+	}
+}
+
+// closeAndRecv closes sending side of the stream and waits for the summary from receiveSummary.
+func closeAndRecv(stream pb.PortService_StreamCreatePortsClient, summaries <-chan summaryResult,
+) (*pb.IngestSummary, error) {
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("failed to close ports stream to ports service: %w", err)
+	}
+	result := <-summaries
+	if result.err != nil {
+		return nil, fmt.Errorf("failed to create ports in ports service: %w", result.err)
+	}
+	return result.summary, nil
+}
+
+// abort ends the stream when the files can't be decoded any further.
+func abort(stream pb.PortService_StreamCreatePortsClient, summaries <-chan summaryResult, opts *pb.IngestOptions,
+	abortErr *abortError,
+) (*pb.IngestSummary, error) {
+	summary := &pb.IngestSummary{DryRun: opts.GetDryRun()}
+	if !atomic(opts) {
+		var err error
+		if summary, err = closeAndRecv(stream, summaries); err != nil {
+			return nil, err
+		}
+	}
+	summary.Aborted = true
+	summary.Rejected++
+	summary.Errors = append(summary.Errors, &pb.PortError{Reason: abortErr.Error(), Offset: abortErr.offset})
+	return summary, nil
+}
+
+// atomic tells whether ports are stored in a single transaction once all of them are received.
+func atomic(opts *pb.IngestOptions) bool {
+	return opts.GetAtomic() || opts.GetReplace() || opts.GetDryRun() ||
+		opts.GetErrorPolicy() == pb.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING
+}
+
+func portToPB(port *Port) *pb.Port {
+	var location *pb.GeoPoint
+	if port.Location != nil {
+		location = &pb.GeoPoint{Lat: port.Location.Lat, Lon: port.Location.Lon}
+	}
+	return &pb.Port{
+		Id:       port.ID,
+		Name:     port.Name,
+		City:     port.City,
+		Country:  port.Country,
+		Alias:    port.Alias,
+		Location: location,
+		Province: port.Province,
+		Unlocs:   port.Unlocs,
+		Code:     port.Code,
+	}
+}
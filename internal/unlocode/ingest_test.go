@@ -0,0 +1,114 @@
+package unlocode
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/arturskrzydlo/ports/internal/common/pb"
+	"github.com/arturskrzydlo/ports/internal/ports"
+	"github.com/arturskrzydlo/ports/internal/ports/adapters"
+)
+
+const (
+	ajmanRow = `,"AE","AJM","Ajman","Ajman","AJ","1-------","AI","0601","","2524N 05530E",""` + "\n"
+	// gdanskRow has malformed coordinates
+	gdanskRow = `,"PL","GDN","Gdańsk","Gdansk","22","1234----","AI","9501",,"5475N 01839E",` + "\n"
+)
+
+func TestImportingCodeList(t *testing.T) {
+	t.Run("should count offsets of ports from the beginning of the first file", func(t *testing.T) {
+		// given
+		client := startPortsService(t)
+		files := createFiles(t, ajmanRow, ajmanRow+gdanskRow)
+
+		// when
+		summary, err := Import(context.Background(), client, &pb.IngestOptions{}, files, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"AEAJM"}, summary.CreatedIds)
+		assert.Equal(t, []string{"AEAJM"}, summary.UpdatedIds)
+		require.Len(t, summary.Errors, 1)
+		assert.Equal(t, "PLGDN", summary.Errors[0].PortId)
+		assert.Equal(t, int64(2*len(ajmanRow)), summary.Errors[0].Offset)
+	})
+
+	t.Run("should abort import on row which isn't UN/LOCODE entry naming its file", func(t *testing.T) {
+		// given
+		client := startPortsService(t)
+		files := createFiles(t, ajmanRow, "id,name\n")
+
+		// when
+		summary, err := Import(context.Background(), client, &pb.IngestOptions{}, files, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, summary.Aborted)
+		assert.Equal(t, []string{"AEAJM"}, summary.CreatedIds)
+		require.Len(t, summary.Errors, 1)
+		assert.Equal(t, int64(len(ajmanRow)), summary.Errors[0].Offset)
+		assert.Equal(t, files[1].Name()+": row at offset 0 isn't UN/LOCODE entry, it has 2 columns instead of at least 11",
+			summary.Errors[0].Reason)
+	})
+
+	t.Run("should store nothing of atomic import aborted by the row", func(t *testing.T) {
+		// given
+		client := startPortsService(t)
+		files := createFiles(t, ajmanRow, "id,name\n")
+
+		// when
+		summary, err := Import(context.Background(), client, &pb.IngestOptions{Atomic: true}, files, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, summary.Aborted)
+		assert.Empty(t, summary.CreatedIds)
+		_, err = client.GetPort(context.Background(), &pb.GetPortRequest{Id: "AEAJM"})
+		assert.Error(t, err)
+	})
+}
+
+// startPortsService serves ports service with in-memory repository until the test ends.
+func startPortsService(t *testing.T) pb.PortServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterPortServiceServer(server, ports.NewPortsService(zap.NewNop(), adapters.NewInMemoryRepo(zap.NewNop())))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewPortServiceClient(conn)
+}
+
+// createFiles writes the code list files and opens them for reading.
+func createFiles(t *testing.T, contents ...string) []*os.File {
+	t.Helper()
+	files := make([]*os.File, len(contents))
+	for i, content := range contents {
+		path := filepath.Join(t.TempDir(), fmt.Sprintf("CodeListPart%d.csv", i+1))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { file.Close() })
+		files[i] = file
+	}
+	return files
+}
@@ -0,0 +1,193 @@
+package webapp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvContentType is a media type of comma separated values with a header row, see csvColumns
+const csvContentType = "text/csv"
+
+// csvListSeparator separates items of list fields, i.e. alias, regions and unlocs, within a single cell
+const csvListSeparator = "|"
+
+// csvColumns are columns of ports in csv, in order they're exported in. Uploaded files may have them in
+// any order, but they need a header row naming them. Location is kept in latitude and longitude columns.
+var csvColumns = []string{
+	"id", "name", "city", "country", "alias", "regions", "latitude", "longitude",
+	"province", "timezone", "unlocs", "code",
+}
+
+// csvPortIterator returns a function yielding consecutive ports read from csv rows. It returns io.EOF
+// when there are no more ports to read.
+func csvPortIterator(body io.Reader) func() (*PortEntry, error) {
+	reader := csv.NewReader(bufio.NewReader(body))
+	var columns map[string]int
+	return func() (*PortEntry, error) {
+		if columns == nil {
+			header, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to read csv header: %w", ErrInvalidRequest, err)
+			}
+			if columns, err = csvHeaderColumns(header); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+			}
+		}
+
+		// the offset points at the row, unless it's preceded by blank lines
+		entry := &PortEntry{Port: &Port{}, Offset: reader.InputOffset()}
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if errors.Is(err, csv.ErrFieldCount) {
+			// the row is rejected, but next rows can be read
+			entry.Port.ID = csvCell(record, columns, "id")
+			entry.DecodeErr = fmt.Errorf("failed to decode port: row has %d columns, header has %d",
+				len(record), len(columns))
+			return entry, nil
+		}
+		if err != nil {
+			return nil, &bodyError{
+				offset: entry.Offset,
+				err:    fmt.Errorf("failed to decode port at offset %d: %w", entry.Offset, err),
+			}
+		}
+		entry.Port, entry.DecodeErr = csvPort(record, columns)
+		return entry, nil
+	}
+}
+
+// csvHeaderColumns maps names of columns, compared ignoring case, to their positions in the header row.
+func csvHeaderColumns(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(csvColumns))
+	for _, column := range csvColumns {
+		known[column] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// spreadsheets tend to start files with byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown csv column %q, columns must be among %s", name, strings.Join(csvColumns, ", "))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("csv column %q is repeated", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, errors.New("csv header must have id column")
+	}
+	return columns, nil
+}
+
+// csvPort creates a port from csv row. The port has its ID set even when the row can't be decoded.
+func csvPort(record []string, columns map[string]int) (*Port, error) {
+	cell := func(column string) string {
+		return csvCell(record, columns, column)
+	}
+	port := &Port{
+		ID:       cell("id"),
+		Name:     cell("name"),
+		City:     cell("city"),
+		Country:  cell("country"),
+		Alias:    csvList(cell("alias")),
+		Regions:  csvList(cell("regions")),
+		Province: cell("province"),
+		Timezone: cell("timezone"),
+		Unlocs:   csvList(cell("unlocs")),
+		Code:     cell("code"),
+	}
+
+	lat, lon := strings.TrimSpace(cell("latitude")), strings.TrimSpace(cell("longitude"))
+	if lat == "" && lon == "" {
+		return port, nil
+	}
+	if lat == "" || lon == "" {
+		return &Port{ID: port.ID}, errors.New("failed to decode port: latitude and longitude must be set together")
+	}
+	location := &GeoPoint{}
+	var err error
+	if location.Lat, err = strconv.ParseFloat(lat, 64); err != nil {
+		return &Port{ID: port.ID}, fmt.Errorf("failed to decode port: latitude must be a number, got %q", lat)
+	}
+	if location.Lon, err = strconv.ParseFloat(lon, 64); err != nil {
+		return &Port{ID: port.ID}, fmt.Errorf("failed to decode port: longitude must be a number, got %q", lon)
+	}
+	port.Location = location
+	return port, nil
+}
+
+// csvCell returns value of the column in csv row, which is empty when the column is missing.
+func csvCell(record []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+// csvList splits cell of list field into its items, skipping empty ones.
+func csvList(cell string) []string {
+	var items []string
+	for _, item := range strings.Split(cell, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeCSVPorts writes a header row followed by a row of every port, see csvColumns.
+func writeCSVPorts(w io.Writer, nextPort func() (*Port, error)) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for {
+		port, err := nextPort()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err = writer.Write(csvRecord(port)); err != nil {
+			return fmt.Errorf("failed to encode port %s: %w", port.ID, err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRecord returns csv row of the port in order of csvColumns.
+func csvRecord(port *Port) []string {
+	var lat, lon string
+	location := port.Location
+	if location == nil && len(port.Coordinates) == 2 {
+		location = &GeoPoint{Lat: port.Coordinates[1], Lon: port.Coordinates[0]}
+	}
+	if location != nil {
+		lat = strconv.FormatFloat(location.Lat, 'f', -1, 64)
+		lon = strconv.FormatFloat(location.Lon, 'f', -1, 64)
+	}
+	return []string{
+		port.ID, port.Name, port.City, port.Country,
+		strings.Join(port.Alias, csvListSeparator), strings.Join(port.Regions, csvListSeparator),
+		lat, lon, port.Province, port.Timezone,
+		strings.Join(port.Unlocs, csvListSeparator), port.Code,
+	}
+}
@@ -0,0 +1,257 @@
+package webapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+)
+
+func (s *serviceHandlerSuite) TestImportingCSVPorts() {
+	s.Run("should map columns in any order onto ports", func() {
+		// given
+		s.SetupTest()
+		body := "\ufeffID,Name,Alias,Latitude,Longitude,Unlocs,Regions\n" +
+			"AEAJM,Ajman,,25.4052165,55.5136433,AEAJM,\n" +
+			"AEAUH,Abu Dhabi,Abu Zabi | Abu Dhabi Port,,,AEAUH|AEAUX,Middle East\n"
+		req := httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		s.Assert().Equal(map[string]*Port{
+			"AEAJM": {
+				ID:       "AEAJM",
+				Name:     "Ajman",
+				Unlocs:   []string{"AEAJM"},
+				Location: &GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+			},
+			"AEAUH": {
+				ID:      "AEAUH",
+				Name:    "Abu Dhabi",
+				Alias:   []string{"Abu Zabi", "Abu Dhabi Port"},
+				Regions: []string{"Middle East"},
+				Unlocs:  []string{"AEAUH", "AEAUX"},
+			},
+		}, s.svc.ports)
+	})
+
+	s.Run("should reject rows which aren't ports with their offsets", func() {
+		// given
+		s.SetupTest()
+		body := "id,name,latitude,longitude\n" +
+			"AEAJM,Ajman,north,55.5\n" +
+			"AEAUH,Abu Dhabi,24.4\n" +
+			"AEDXB,Dubai\n" +
+			"AEFJR,Fujairah,25.1,56.3\n"
+		req := httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", csvContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().Equal(uint32(1), summary.Created)
+		s.Assert().Equal([]PortError{
+			{PortID: "AEAJM", Reason: `failed to decode port: latitude must be a number, got "north"`, Offset: 27},
+			{PortID: "AEAUH", Reason: "failed to decode port: row has 3 columns, header has 4", Offset: 50},
+			{PortID: "AEDXB", Reason: "failed to decode port: row has 2 columns, header has 4", Offset: 71},
+		}, summary.Errors)
+		s.Assert().Equal([]string{"AEFJR"}, summary.CreatedIDs)
+	})
+
+	invalidBodies := map[string]string{
+		"unknown column":    "id,name,lat\nAEAJM,Ajman,25.4\n",
+		"repeated column":   "id,name,Name\nAEAJM,Ajman,Ajman\n",
+		"missing id column": "name\nAjman\n",
+	}
+	for name, body := range invalidBodies {
+		s.Run("should reject csv with "+name, func() {
+			// given
+			s.SetupTest()
+			req := httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", csvContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+			s.assertErrorBody(recorder, "invalid_argument")
+			s.Assert().Empty(s.svc.ports)
+		})
+	}
+
+	s.Run("should abort ingestion at malformed csv quote", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports",
+			bytes.NewBufferString("id,name\nAEAJM,Ajman\nAEAUH,\"Abu Dhabi\n"))
+		req.Header.Set("Content-Type", csvContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusUnprocessableEntity, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().True(summary.Aborted)
+		s.Assert().Equal([]string{"AEAJM"}, summary.CreatedIDs)
+		s.Require().Len(summary.Errors, 1)
+		s.Assert().Equal(int64(20), summary.Errors[0].Offset)
+	})
+
+	s.Run("should read csv part of multipart form", func() {
+		// given
+		s.SetupTest()
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Disposition": {`form-data; name="ports"; filename="ports.csv"`},
+			"Content-Type":        {csvContentType},
+		})
+		s.Require().NoError(err)
+		_, err = part.Write([]byte("id,name\nAEAJM,Ajman\n"))
+		s.Require().NoError(err)
+		s.Require().NoError(writer.Close())
+		req := httptest.NewRequest(http.MethodPost, "/ports", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		s.Assert().Equal(map[string]*Port{"AEAJM": {ID: "AEAJM", Name: "Ajman"}}, s.svc.ports)
+	})
+}
+
+func (s *serviceHandlerSuite) TestListingPortsAsCSV() {
+	ports := map[string]*Port{
+		"AEAJM": {
+			ID:       "AEAJM",
+			Name:     "Ajman",
+			City:     "Ajman",
+			Country:  "United Arab Emirates",
+			Location: &GeoPoint{Lat: 25.4052165, Lon: 55.5136433},
+			Province: "Ajman",
+			Timezone: "Asia/Dubai",
+			Unlocs:   []string{"AEAJM"},
+			Code:     "52000",
+		},
+		"AEAUH": {
+			ID:      "AEAUH",
+			Name:    "Abu Dhabi, Port",
+			Alias:   []string{"Abu Zabi", "Abu Dhabi Port"},
+			Regions: []string{"Middle East"},
+			Code:    "52001",
+		},
+	}
+	const csvPorts = "id,name,city,country,alias,regions,latitude,longitude,province,timezone,unlocs,code\n" +
+		"AEAJM,Ajman,Ajman,United Arab Emirates,,,25.4052165,55.5136433,Ajman,Asia/Dubai,AEAJM,52000\n" +
+		"AEAUH,\"Abu Dhabi, Port\",,,Abu Zabi|Abu Dhabi Port,Middle East,,,,,,52001\n"
+
+	urls := map[string]struct {
+		url    string
+		accept string
+	}{
+		"list accepting csv":   {url: "/ports", accept: "text/csv"},
+		"export of csv format": {url: "/ports/export?format=csv"},
+	}
+	for name, test := range urls {
+		s.Run("should write all ports in "+name, func() {
+			// given
+			s.SetupTest()
+			for id, port := range ports {
+				s.svc.ports[id] = port
+			}
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			req.Header.Set("Accept", test.accept)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(http.StatusOK, recorder.Code)
+			s.Assert().Equal(csvContentType, recorder.Header().Get("Content-Type"))
+			s.Assert().Equal(csvPorts, recorder.Body.String())
+
+			// exported ports are imported back as they were
+			s.SetupTest()
+			req = httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(csvPorts))
+			req.Header.Set("Content-Type", csvContentType)
+			s.Require().Equal(http.StatusCreated, s.serve(req).Code)
+			s.Assert().Equal(ports, s.svc.ports)
+		})
+	}
+
+	s.Run("should write legacy coordinates of ports", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Coordinates: []float64{55.5136433, 25.4052165}}
+		req := httptest.NewRequest(http.MethodGet, "/ports", nil)
+		req.Header.Set("Accept", "text/csv")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().Equal("id,name,city,country,alias,regions,latitude,longitude,province,timezone,unlocs,code\n"+
+			"AEAJM,,,,,,25.4052165,55.5136433,,,,\n", recorder.Body.String())
+	})
+
+	s.Run("should refuse csv summary of uploaded ports", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(csvPorts))
+		req.Header.Set("Content-Type", csvContentType)
+		req.Header.Set("Accept", "text/csv")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusNotAcceptable, recorder.Code)
+		s.assertErrorBody(recorder, "not_acceptable")
+		s.Assert().Empty(s.svc.ports)
+	})
+
+	s.Run("should reject paging of csv", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodGet, "/ports?page_size=10", nil)
+		req.Header.Set("Accept", "text/csv")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+	})
+
+	s.Run("should report failure of Ports service with its status code", func() {
+		// given
+		s.SetupTest()
+		s.svc.err = &ServiceError{kind: ErrServiceUnavailable, Message: "connection refused"}
+		req := httptest.NewRequest(http.MethodGet, "/ports", nil)
+		req.Header.Set("Accept", "text/csv")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusServiceUnavailable, recorder.Code)
+		s.assertErrorBody(recorder, "unavailable")
+	})
+}
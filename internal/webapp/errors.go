@@ -0,0 +1,114 @@
+package webapp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorCodes name kinds of errors by their HTTP status codes, so clients don't have to parse messages.
+var errorCodes = map[int]string{
+	http.StatusBadRequest:            "invalid_argument",
+	http.StatusNotFound:              "not_found",
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusNotAcceptable:         "not_acceptable",
+	http.StatusConflict:              "already_exists",
+	http.StatusRequestEntityTooLarge: "too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusInternalServerError:   "internal",
+	http.StatusServiceUnavailable:    "unavailable",
+}
+
+// errorResp is a body of every error response.
+type errorResp struct {
+	Error string `json:"error_message"`
+	// Code is a machine readable kind of the error, i.e. "not_found"
+	Code            string           `json:"error_code"`
+	FieldViolations []FieldViolation `json:"field_violations,omitempty"`
+}
+
+// FieldViolation describes why a single field of the request is invalid, i.e. "unlocs[1]".
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ServiceError is an error returned by Ports service. It matches one of the webapp errors,
+// i.e. ErrPortNotFound, so it can be checked with errors.Is.
+type ServiceError struct {
+	kind    error
+	Message string
+	// FieldViolations list invalid fields of the request, they're set only for invalid ports
+	FieldViolations []FieldViolation
+}
+
+func (e *ServiceError) Error() string {
+	return e.kind.Error() + ": " + e.Message
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.kind
+}
+
+// serviceErr translates gRPC status returned by Ports service into ServiceError. InvalidArgument is
+// translated into invalidErr, as it means either invalid port or invalid query depending on the call.
+// Errors of other kinds are wrapped with the message.
+func serviceErr(message string, err error, invalidErr error) error {
+	st := status.Convert(err)
+	var kind error
+	switch st.Code() {
+	case codes.NotFound:
+		kind = ErrPortNotFound
+	case codes.InvalidArgument:
+		kind = invalidErr
+	case codes.AlreadyExists:
+		kind = ErrPortAlreadyExists
+	case codes.Unavailable, codes.DeadlineExceeded:
+		kind = ErrServiceUnavailable
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+
+	svcErr := &ServiceError{kind: kind, Message: st.Message()}
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.FieldViolations {
+			svcErr.FieldViolations = append(svcErr.FieldViolations, FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Description,
+			})
+		}
+	}
+	return svcErr
+}
+
+// errorStatusCode returns HTTP status code matching the error returned by a handler or PortsService.
+func errorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrPortNotFound), errors.Is(err, ErrImportNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidPort), errors.Is(err, ErrInvalidQuery), errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrPortAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrServiceUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, ErrNotAcceptable):
+		return http.StatusNotAcceptable
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrImportTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
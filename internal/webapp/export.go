@@ -0,0 +1,173 @@
+package webapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// exportFormats maps values of format query param of the export to media types of the response.
+var exportFormats = map[string]string{
+	"json":   jsonContentType,
+	"ndjson": ndjsonContentType,
+	"csv":    csvContentType,
+}
+
+// portsWriters write streamed ports in the given media type.
+var portsWriters = map[string]func(w io.Writer, nextPort func() (*Port, error)) error{
+	jsonContentType:   writeKeyedPorts,
+	ndjsonContentType: writeNDJSONPorts,
+	csvContentType:    writeCSVPorts,
+}
+
+// exportedPort is a port in the format of uploaded files, i.e. testdata/ports.json. Its location is kept
+// only in coordinates and ID is set only in newline delimited json, as json object is keyed by port ids.
+type exportedPort struct {
+	ID          string    `json:"id,omitempty"`
+	Name        string    `json:"name"`
+	City        string    `json:"city"`
+	Country     string    `json:"country"`
+	Alias       []string  `json:"alias"`
+	Regions     []string  `json:"regions"`
+	Coordinates []float64 `json:"coordinates"`
+	Province    string    `json:"province"`
+	Timezone    string    `json:"timezone"`
+	Unlocs      []string  `json:"unlocs"`
+	Code        string    `json:"code"`
+}
+
+func newExportedPort(port *Port) *exportedPort {
+	coordinates := port.Coordinates
+	if port.Location != nil {
+		coordinates = []float64{port.Location.Lon, port.Location.Lat}
+	}
+	return &exportedPort{
+		ID:          port.ID,
+		Name:        port.Name,
+		City:        port.City,
+		Country:     port.Country,
+		Alias:       nonNil(port.Alias),
+		Regions:     nonNil(port.Regions),
+		Coordinates: nonNil(coordinates),
+		Province:    port.Province,
+		Timezone:    port.Timezone,
+		Unlocs:      nonNil(port.Unlocs),
+		Code:        port.Code,
+	}
+}
+
+// nonNil returns empty slice instead of nil one, so it's encoded as empty json array like in uploaded files.
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+// exportPorts handles /ports/export?format=ndjson
+func (sh *ServiceHandler) exportPorts(respWriter http.ResponseWriter, request *http.Request) {
+	sh.route(respWriter, request, methodHandlers{http.MethodGet: sh.export},
+		jsonContentType, ndjsonContentType, csvContentType)
+}
+
+// export streams ports in the format they're uploaded in, either as a json object keyed by port ids,
+// as newline delimited json or as csv. The format is selected by format query param or by Accept header.
+func (sh *ServiceHandler) export(request *http.Request) (*response, error) {
+	values := request.URL.Query()
+	mediaType := responseMediaType(request)
+	if format := values.Get("format"); format != "" {
+		var ok bool
+		if mediaType, ok = exportFormats[format]; !ok {
+			return nil, invalidRequestErr(fmt.Errorf("format must be one of json, ndjson or csv, got %q", format))
+		}
+	}
+	query, err := parsePortsQuery(values)
+	if err != nil {
+		return nil, invalidRequestErr(err)
+	}
+	return sh.streamPorts(request, query, mediaType)
+}
+
+// streamPorts responds with all ports matching the query written in the media type.
+func (sh *ServiceHandler) streamPorts(request *http.Request, query PortsQuery, mediaType string) (*response, error) {
+	nextPort, err := sh.svc.StreamPorts(request.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+	// the first port is fetched before the response is sent, so failure of Ports service is reported
+	// with its status code
+	first, firstErr := nextPort()
+	if firstErr != nil && !errors.Is(firstErr, io.EOF) {
+		return nil, firstErr
+	}
+	peeked := true
+	ports := func() (*Port, error) {
+		if peeked {
+			peeked = false
+			return first, firstErr
+		}
+		return nextPort()
+	}
+
+	writePorts := portsWriters[mediaType]
+	return &response{
+		status:      http.StatusOK,
+		contentType: mediaType,
+		stream: func(w io.Writer) error {
+			return writePorts(w, ports)
+		},
+	}, nil
+}
+
+// writeKeyedPorts writes ports as a json object keyed by port ids, one port per line. The object is left
+// incomplete when the ports can't be fetched, so the output can't be mistaken for all of them.
+func writeKeyedPorts(w io.Writer, nextPort func() (*Port, error)) error {
+	separator := "{\n"
+	for {
+		port, err := nextPort()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// strings are always encoded
+		key, _ := json.Marshal(port.ID)
+		exported := newExportedPort(port)
+		exported.ID = ""
+		value, err := json.Marshal(exported)
+		if err != nil {
+			return fmt.Errorf("failed to encode port %s: %w", port.ID, err)
+		}
+		if _, err = fmt.Fprintf(w, "%s%s: %s", separator, key, value); err != nil {
+			return err
+		}
+		separator = ",\n"
+	}
+	if separator == "{\n" {
+		_, err := io.WriteString(w, "{}\n")
+		return err
+	}
+	_, err := io.WriteString(w, "\n}\n")
+	return err
+}
+
+// writeNDJSONPorts writes every port in its own line, along with its id.
+func writeNDJSONPorts(w io.Writer, nextPort func() (*Port, error)) error {
+	encoder := json.NewEncoder(w)
+	for {
+		port, err := nextPort()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = encoder.Encode(newExportedPort(port)); err != nil {
+			return fmt.Errorf("failed to encode port %s: %w", port.ID, err)
+		}
+	}
+}
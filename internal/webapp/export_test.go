@@ -0,0 +1,150 @@
+package webapp
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (s *serviceHandlerSuite) TestExportingPorts() {
+	ports := map[string]*Port{
+		"AEAJM": {
+			ID:          "AEAJM",
+			Name:        "Ajman",
+			City:        "Ajman",
+			Country:     "United Arab Emirates",
+			Alias:       []string{},
+			Regions:     []string{},
+			Coordinates: []float64{55.5136433, 25.4052165},
+			Province:    "Ajman",
+			Timezone:    "Asia/Dubai",
+			Unlocs:      []string{"AEAJM"},
+			Code:        "52000",
+		},
+		"AEAUH": {
+			ID:          "AEAUH",
+			Name:        "Abu Dhabi",
+			Alias:       []string{"Abu Zabi"},
+			Regions:     []string{},
+			Coordinates: []float64{},
+			Unlocs:      []string{},
+			Code:        "52001",
+		},
+	}
+	const keyedPorts = `{
+"AEAJM": {"name":"Ajman","city":"Ajman","country":"United Arab Emirates","alias":[],"regions":[],` +
+		`"coordinates":[55.5136433,25.4052165],"province":"Ajman","timezone":"Asia/Dubai","unlocs":["AEAJM"],"code":"52000"},
+"AEAUH": {"name":"Abu Dhabi","city":"","country":"","alias":["Abu Zabi"],"regions":[],"coordinates":[],` +
+		`"province":"","timezone":"","unlocs":[],"code":"52001"}
+}
+`
+	const ndjsonPorts = `{"id":"AEAJM","name":"Ajman","city":"Ajman","country":"United Arab Emirates","alias":[],` +
+		`"regions":[],"coordinates":[55.5136433,25.4052165],"province":"Ajman","timezone":"Asia/Dubai",` +
+		`"unlocs":["AEAJM"],"code":"52000"}
+{"id":"AEAUH","name":"Abu Dhabi","city":"","country":"","alias":["Abu Zabi"],"regions":[],"coordinates":[],` +
+		`"province":"","timezone":"","unlocs":[],"code":"52001"}
+`
+
+	tests := map[string]struct {
+		url                 string
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		"json object keyed by port ids by default": {
+			url:                 "/ports/export",
+			expectedContentType: jsonContentType,
+			expectedBody:        keyedPorts,
+		},
+		"newline delimited json selected by format": {
+			url:                 "/ports/export?format=ndjson",
+			accept:              jsonContentType,
+			expectedContentType: ndjsonContentType,
+			expectedBody:        ndjsonPorts,
+		},
+		"newline delimited json selected by Accept header": {
+			url:                 "/ports/export",
+			accept:              ndjsonContentType,
+			expectedContentType: ndjsonContentType,
+			expectedBody:        ndjsonPorts,
+		},
+	}
+
+	for name, test := range tests {
+		s.Run("should export "+name, func() {
+			// given
+			s.SetupTest()
+			for id, port := range ports {
+				s.svc.ports[id] = port
+			}
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			req.Header.Set("Accept", test.accept)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(http.StatusOK, recorder.Code)
+			s.Assert().Equal(test.expectedContentType, recorder.Header().Get("Content-Type"))
+			s.Assert().Equal(test.expectedBody, recorder.Body.String())
+
+			// exported ports are imported back as they were
+			exported := recorder.Body.String()
+			s.SetupTest()
+			req = httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(exported))
+			req.Header.Set("Content-Type", test.expectedContentType)
+			s.Require().Equal(http.StatusCreated, s.serve(req).Code)
+			s.Assert().Equal(ports, s.svc.ports)
+		})
+	}
+
+	s.Run("should export empty json object when there are no ports", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/export", nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().Equal("{}\n", recorder.Body.String())
+	})
+
+	s.Run("should pass filter to Ports service", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/export?country=Poland&order_by=name", nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().Equal("Poland", s.svc.query.Filter.Country)
+		s.Assert().Equal(OrderByName, s.svc.query.OrderBy)
+	})
+
+	s.Run("should reject unknown format", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/export?format=xml", nil))
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+	})
+
+	s.Run("should report failure of Ports service with its status code", func() {
+		// given
+		s.SetupTest()
+		s.svc.err = &ServiceError{kind: ErrServiceUnavailable, Message: "connection refused"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/export", nil))
+
+		// then
+		s.Assert().Equal(http.StatusServiceUnavailable, recorder.Code)
+		s.assertErrorBody(recorder, "unavailable")
+	})
+}
@@ -0,0 +1,391 @@
+package webapp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	importsEndpointName = "imports"
+	// finishedImportRetention is how long finished imports are kept, so their results can be fetched
+	finishedImportRetention = time.Hour
+)
+
+var (
+	// ErrImportNotFound is returned when requested import job doesn't exist or was already forgotten.
+	ErrImportNotFound = errors.New("import not found")
+	// ErrImportTooLarge is returned when uploaded ports are larger than the limit of imported files.
+	ErrImportTooLarge = errors.New("import too large")
+)
+
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "running"
+	ImportSucceeded ImportStatus = "succeeded"
+	ImportFailed    ImportStatus = "failed"
+	ImportCanceled  ImportStatus = "canceled"
+)
+
+// ImportJob is a state of ports import running in background.
+type ImportJob struct {
+	ID          string       `json:"id"`
+	Status      ImportStatus `json:"status"`
+	ErrorPolicy ErrorPolicy  `json:"error_policy"`
+	Mode        IngestMode   `json:"mode"`
+	Atomic      bool         `json:"atomic"`
+	DryRun      bool         `json:"dry_run"`
+	// Processed is the number of ports read from the file and sent to Ports service so far
+	Processed uint32 `json:"processed"`
+	// Created, Updated and Deleted are known once ports are sent to Ports service, or once the import is
+	// canceled for the ones stored before. Failed ports are the ones rejected by Ports service, they're
+	// counted and listed in Errors as soon as they're rejected
+	Created    uint32      `json:"created"`
+	Updated    uint32      `json:"updated"`
+	Deleted    uint32      `json:"deleted"`
+	DeletedIDs []string    `json:"deleted_ids"`
+	Failed     uint32      `json:"failed"`
+	Errors     []PortError `json:"errors"`
+	// Error is the reason why the whole import failed
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// importJob is an import running in background. Processed ports are counted apart from the rest
+// of the state, as they're updated for every port.
+type importJob struct {
+	mu        sync.Mutex
+	state     ImportJob
+	processed atomic.Uint32
+	cancel    context.CancelFunc
+	// done is closed once the import finishes
+	done chan struct{}
+}
+
+func (j *importJob) snapshot() *ImportJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	state := j.state
+	state.Processed = j.processed.Load()
+	state.Errors = append([]PortError{}, j.state.Errors...)
+	state.DeletedIDs = append([]string{}, j.state.DeletedIDs...)
+	return &state
+}
+
+func (j *importJob) finish(summary *IngestSummary, err, decodeErr error, canceled bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	finishedAt := time.Now()
+	j.state.FinishedAt = &finishedAt
+	switch {
+	case canceled:
+		j.state.Status = ImportCanceled
+		// non-atomic import reports ports stored before it was canceled
+		if summary != nil {
+			j.record(summary)
+		}
+	case err != nil:
+		j.state.Status = ImportFailed
+		j.state.Error = err.Error()
+	default:
+		j.state.Status = ImportSucceeded
+		j.record(summary)
+		switch {
+		case summary.Aborted && decodeErr != nil:
+			j.state.Status = ImportFailed
+			j.state.Error = decodeErr.Error()
+		case summary.Aborted:
+			j.state.Status = ImportFailed
+			j.state.Error = fmt.Sprintf("import aborted by %s error policy", j.state.ErrorPolicy)
+		}
+	}
+}
+
+// record replaces counts of the job with the ones of the summary, it has to be called with mu held.
+func (j *importJob) record(summary *IngestSummary) {
+	j.state.Created = summary.Created
+	j.state.Updated = summary.Updated
+	j.state.Deleted = summary.Deleted
+	j.state.DeletedIDs = summary.DeletedIDs
+	j.state.Failed = summary.Rejected
+	j.state.Errors = summary.Errors
+}
+
+// reject records the port rejected by Ports service while the import is running.
+func (j *importJob) reject(portErr PortError) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.state.Failed++
+	j.state.Errors = append(j.state.Errors, portErr)
+}
+
+// expired reports whether the job finished long enough ago to be forgotten.
+func (j *importJob) expired(now time.Time) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state.FinishedAt != nil && now.Sub(*j.state.FinishedAt) > finishedImportRetention
+}
+
+// importer runs imports of ports in background. Ports are spooled to a file in dir first, so the
+// request can be answered as soon as its body is received.
+type importer struct {
+	svc PortsService
+	dir string
+	// maxSize limits size of the spooled file in bytes, zero means there is no limit
+	maxSize int64
+	log     *zap.Logger
+
+	mu     sync.Mutex
+	jobs   map[string]*importJob
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newImporter(svc PortsService, dir string, maxSize int64, logger *zap.Logger) *importer {
+	return &importer{svc: svc, dir: dir, maxSize: maxSize, log: logger, jobs: make(map[string]*importJob)}
+}
+
+// start saves ports read from body of the given media type and imports them in background.
+func (im *importer) start(body io.Reader, mediaType string, opts IngestOptions) (*ImportJob, error) {
+	file, err := os.CreateTemp(im.dir, "ports-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import file: %w", err)
+	}
+	if im.maxSize > 0 {
+		// a byte over the limit is read to tell the body exceeding it from the one of exactly its size
+		body = io.LimitReader(body, im.maxSize+1)
+	}
+	size, err := io.Copy(file, body)
+	if err != nil {
+		im.removeFile(file)
+		return nil, invalidRequestErr(fmt.Errorf("failed to read ports: %w", err))
+	}
+	if im.maxSize > 0 && size > im.maxSize {
+		im.removeFile(file)
+		return nil, fmt.Errorf("%w: ports can't be larger than %d bytes", ErrImportTooLarge, im.maxSize)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		im.removeFile(file)
+		return nil, fmt.Errorf("failed to rewind import file: %w", err)
+	}
+	id, err := newImportID()
+	if err != nil {
+		im.removeFile(file)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &importJob{
+		state: ImportJob{
+			ID:          id,
+			Status:      ImportRunning,
+			ErrorPolicy: opts.ErrorPolicy,
+			Mode:        opts.Mode,
+			Atomic:      opts.Atomic,
+			DryRun:      opts.DryRun,
+			Errors:      []PortError{},
+			StartedAt:   time.Now(),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	im.mu.Lock()
+	if im.closed {
+		im.mu.Unlock()
+		cancel()
+		im.removeFile(file)
+		return nil, fmt.Errorf("%w: webapp is shutting down", ErrServiceUnavailable)
+	}
+	im.forgetExpired(time.Now())
+	im.jobs[id] = job
+	im.wg.Add(1)
+	im.mu.Unlock()
+
+	go im.run(ctx, job, file, mediaType)
+	return job.snapshot(), nil
+}
+
+func (im *importer) run(ctx context.Context, job *importJob, file *os.File, mediaType string) {
+	defer im.wg.Done()
+	defer close(job.done)
+	defer im.removeFile(file)
+	defer job.cancel()
+
+	nextPort := decodePorts(file, mediaType)
+	opts := IngestOptions{
+		ErrorPolicy: job.state.ErrorPolicy,
+		Mode:        job.state.Mode,
+		Atomic:      job.state.Atomic,
+		DryRun:      job.state.DryRun,
+		OnRejected:  job.reject,
+	}
+	// decodeErr stops reading of the file, it's the reason of aborted import then
+	var decodeErr error
+	summary, err := im.svc.CreatePorts(ctx, opts, func() (*PortEntry, error) {
+		entry, err := nextPort()
+		switch {
+		case err == nil:
+			job.processed.Add(1)
+		case !errors.Is(err, io.EOF):
+			decodeErr = err
+		}
+		return entry, err
+	})
+	if err != nil && ctx.Err() == nil {
+		im.log.Warn("import failed", zap.String("import_id", job.state.ID), zap.Error(err))
+	}
+	job.finish(summary, err, decodeErr, ctx.Err() != nil)
+}
+
+// get returns current state of the import.
+func (im *importer) get(id string) (*ImportJob, error) {
+	im.mu.Lock()
+	job, ok := im.jobs[id]
+	im.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrImportNotFound, id)
+	}
+	return job.snapshot(), nil
+}
+
+// cancel stops the import and waits until it's stopped. Finished imports are left as they are.
+func (im *importer) cancel(ctx context.Context, id string) (*ImportJob, error) {
+	im.mu.Lock()
+	job, ok := im.jobs[id]
+	im.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrImportNotFound, id)
+	}
+
+	job.cancel()
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return job.snapshot(), nil
+}
+
+// close cancels all running imports and waits until they're stopped. New imports can't be started then.
+func (im *importer) close(ctx context.Context) error {
+	im.mu.Lock()
+	im.closed = true
+	for _, job := range im.jobs {
+		job.cancel()
+	}
+	im.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		im.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to stop imports: %w", ctx.Err())
+	}
+}
+
+// forgetExpired drops imports which finished more than finishedImportRetention ago, it has to be called with mu held.
+func (im *importer) forgetExpired(now time.Time) {
+	for id, job := range im.jobs {
+		if job.expired(now) {
+			delete(im.jobs, id)
+		}
+	}
+}
+
+func (im *importer) removeFile(file *os.File) {
+	if err := file.Close(); err != nil {
+		im.log.Warn("failed to close import file", zap.Error(err))
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		im.log.Warn("failed to remove import file", zap.Error(err))
+	}
+}
+
+func newImportID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate import id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func (sh *ServiceHandler) imports(respWriter http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodPost {
+		sh.allowUpload(respWriter)
+	}
+	sh.route(respWriter, request, methodHandlers{http.MethodPost: sh.startImport})
+}
+
+// importJob handles requests to a single import identified by the last segment of the path, i.e. /imports/6f1c...
+func (sh *ServiceHandler) importJob(respWriter http.ResponseWriter, request *http.Request) {
+	id := importID(request)
+	if id == "" || strings.Contains(id, "/") {
+		sh.writeErr(respWriter, ErrImportNotFound)
+		return
+	}
+
+	sh.route(respWriter, request, methodHandlers{
+		http.MethodGet:    sh.getImport,
+		http.MethodDelete: sh.cancelImport,
+	})
+}
+
+// importID returns id of the import from the last segment of the path.
+func importID(request *http.Request) string {
+	return strings.TrimPrefix(request.URL.Path, "/"+importsEndpointName+"/")
+}
+
+func (sh *ServiceHandler) startImport(request *http.Request) (*response, error) {
+	body, mediaType, err := portsBody(request)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseIngestOptions(request.URL.Query())
+	if err != nil {
+		return nil, invalidRequestErr(err)
+	}
+	job, err := sh.importer.start(body, mediaType, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &response{
+		status: http.StatusAccepted,
+		body:   job,
+		header: http.Header{"Location": {"/" + importsEndpointName + "/" + job.ID}},
+	}, nil
+}
+
+func (sh *ServiceHandler) getImport(request *http.Request) (*response, error) {
+	job, err := sh.importer.get(importID(request))
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: job}, nil
+}
+
+func (sh *ServiceHandler) cancelImport(request *http.Request) (*response, error) {
+	job, err := sh.importer.cancel(request.Context(), importID(request))
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: job}, nil
+}
@@ -0,0 +1,358 @@
+package webapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type importsSuite struct {
+	suite.Suite
+
+	svc     *fakePortsService
+	handler *ServiceHandler
+	mux     *http.ServeMux
+}
+
+func TestImports(t *testing.T) {
+	suite.Run(t, &importsSuite{})
+}
+
+func (s *importsSuite) SetupTest() {
+	s.svc = &fakePortsService{ports: map[string]*Port{}}
+	s.mux = http.NewServeMux()
+	s.handler = NewServiceHandler(s.svc, &http.Server{Handler: s.mux}, 0, 0, zap.NewNop())
+	s.handler.importer.dir = s.T().TempDir()
+	s.handler.Register(s.mux)
+}
+
+func (s *importsSuite) TestImportingPorts() {
+	s.Run("should import ports in background", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.startImport(`{"AEAJM": {"name": "Ajman"}, "AEAUH": {"name": "Abu Dhabi"}}`, jsonContentType)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		s.Assert().NotEmpty(job.ID)
+		s.Assert().Equal("/imports/"+job.ID, recorder.Header().Get("Location"))
+
+		finished := s.awaitImport(job.ID)
+		s.Assert().Equal(ImportSucceeded, finished.Status)
+		s.Assert().Equal(uint32(2), finished.Processed)
+		s.Assert().Equal(uint32(2), finished.Created)
+		s.Assert().NotNil(finished.FinishedAt)
+		s.Assert().Len(s.svc.ports, 2)
+		importFiles, err := os.ReadDir(s.handler.importer.dir)
+		s.Require().NoError(err)
+		s.Assert().Empty(importFiles)
+	})
+
+	s.Run("should report failed import", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.startImport("{\"id\": \"AEAJM\"}\n{\"id\": \n", ndjsonContentType)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		finished := s.awaitImport(job.ID)
+		s.Assert().Equal(ImportFailed, finished.Status)
+		s.Assert().Equal(uint32(1), finished.Processed)
+		s.Assert().Equal(uint32(1), finished.Created)
+		s.Assert().Equal(uint32(1), finished.Failed)
+		s.Assert().Contains(finished.Error, "failed to decode port at offset 16")
+	})
+
+	s.Run("should report rejected ports while import is running", func() {
+		// given
+		s.SetupTest()
+		s.svc.blockCreating = true
+
+		// when
+		recorder := s.startImport(`{"AEAJM": {}, "AEAUH": {"name": 1}}`, jsonContentType)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		var running ImportJob
+		s.Require().Eventually(func() bool {
+			running = s.getImport(job.ID)
+			return running.Failed == 1
+		}, time.Second, 5*time.Millisecond)
+		s.Assert().Equal(ImportRunning, running.Status)
+		s.Require().Len(running.Errors, 1)
+		s.Assert().Equal("AEAUH", running.Errors[0].PortID)
+		s.Require().NoError(s.handler.Shutdown(context.Background()))
+	})
+
+	s.Run("should report import aborted by error policy", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		req := httptest.NewRequest(http.MethodPost, "/imports?error_policy=all_or_nothing",
+			bytes.NewBufferString(`{"AEAJM": {}, "AEAUH": {"name": 1}}`))
+		req.Header.Set("Content-Type", jsonContentType)
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		s.Assert().Equal(AllOrNothing, job.ErrorPolicy)
+		finished := s.awaitImport(job.ID)
+		s.Assert().Equal(ImportFailed, finished.Status)
+		s.Assert().Equal("import aborted by all_or_nothing error policy", finished.Error)
+		s.Assert().Equal(uint32(1), finished.Failed)
+		s.Assert().Zero(finished.Created)
+		s.Assert().Empty(s.svc.ports)
+	})
+
+	s.Run("should import ports atomically", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		req := httptest.NewRequest(http.MethodPost, "/imports?atomic=true", bytes.NewBufferString(`{"AEAJM": {}}`))
+		req.Header.Set("Content-Type", jsonContentType)
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		s.Assert().True(job.Atomic)
+		s.Assert().Equal(ImportSucceeded, s.awaitImport(job.ID).Status)
+		s.Assert().True(s.svc.ingestOpts.Atomic)
+	})
+
+	s.Run("should only report changes of dry run", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		req := httptest.NewRequest(http.MethodPost, "/imports?mode=replace&dry_run=true",
+			bytes.NewBufferString(`{"AEAJM": {}}`))
+		req.Header.Set("Content-Type", jsonContentType)
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		s.Assert().Equal(ReplaceMode, job.Mode)
+		s.Assert().True(job.DryRun)
+		s.Assert().Equal(ImportSucceeded, s.awaitImport(job.ID).Status)
+		s.Assert().Empty(s.svc.ports)
+	})
+
+	s.Run("should reject body larger than the limit", func() {
+		// given
+		s.SetupTest()
+		body := `{"AEAJM": {}}`
+		s.handler.importer.maxSize = int64(len(body) - 1)
+
+		// when
+		recorder := s.startImport(body, jsonContentType)
+
+		// then
+		s.Assert().Equal(http.StatusRequestEntityTooLarge, recorder.Code)
+		s.Assert().Contains(recorder.Body.String(), "too_large")
+		importFiles, err := os.ReadDir(s.handler.importer.dir)
+		s.Require().NoError(err)
+		s.Assert().Empty(importFiles)
+		s.Assert().Zero(s.svc.calls)
+	})
+
+	s.Run("should import body of exactly the limit", func() {
+		// given
+		s.SetupTest()
+		body := `{"AEAJM": {}}`
+		s.handler.importer.maxSize = int64(len(body))
+
+		// when
+		recorder := s.startImport(body, jsonContentType)
+
+		// then
+		s.Require().Equal(http.StatusAccepted, recorder.Code)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+		s.Assert().Equal(uint32(1), s.awaitImport(job.ID).Created)
+	})
+
+	s.Run("should reject body of unsupported media type", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.startImport(`AEAJM,Ajman`, "text/plain")
+
+		// then
+		s.Assert().Equal(http.StatusUnsupportedMediaType, recorder.Code)
+		s.Assert().Empty(s.handler.importer.jobs)
+	})
+}
+
+func (s *importsSuite) TestUploadingLongerThanServerTimeouts() {
+	// given
+	s.SetupTest()
+	s.handler.uploadTimeout = time.Minute
+
+	// when
+	resp := postSlowly(s.T(), s.mux, "/imports", jsonContentType, `{"AEAJM": {"name": "Ajman"},`, ` "AEAUH": {}}`)
+
+	// then
+	s.Require().Equal(http.StatusAccepted, resp.StatusCode)
+	var job ImportJob
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&job))
+	s.Assert().Equal(uint32(2), s.awaitImport(job.ID).Created)
+}
+
+func (s *importsSuite) TestCancelingImport() {
+	s.Run("should cancel running import", func() {
+		// given
+		s.SetupTest()
+		s.svc.blockCreating = true
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(s.startImport(`{"AEAJM": {}}`, jsonContentType).Body).Decode(&job))
+		s.Require().Equal(ImportRunning, s.getImport(job.ID).Status)
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodDelete, "/imports/"+job.ID, nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		var canceled ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&canceled))
+		s.Assert().Equal(ImportCanceled, canceled.Status)
+		s.Assert().Equal(uint32(1), canceled.Created)
+		s.Assert().Equal(ImportCanceled, s.getImport(job.ID).Status)
+	})
+
+	s.Run("should report nothing stored by canceled atomic import", func() {
+		// given
+		s.SetupTest()
+		s.svc.blockCreating = true
+		req := httptest.NewRequest(http.MethodPost, "/imports?atomic=true", bytes.NewBufferString(`{"AEAJM": {}}`))
+		req.Header.Set("Content-Type", jsonContentType)
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(s.serve(req).Body).Decode(&job))
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodDelete, "/imports/"+job.ID, nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		var canceled ImportJob
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&canceled))
+		s.Assert().Equal(ImportCanceled, canceled.Status)
+		s.Assert().Zero(canceled.Created)
+		s.Assert().Empty(s.svc.ports)
+	})
+
+	s.Run("should leave finished import as it is", func() {
+		// given
+		s.SetupTest()
+		var job ImportJob
+		s.Require().NoError(json.NewDecoder(s.startImport(`{"AEAJM": {}}`, jsonContentType).Body).Decode(&job))
+		s.awaitImport(job.ID)
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodDelete, "/imports/"+job.ID, nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().Equal(ImportSucceeded, s.getImport(job.ID).Status)
+	})
+
+	s.Run("should return not found for unknown import", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodDelete, "/imports/unknown", nil))
+
+		// then
+		s.Assert().Equal(http.StatusNotFound, recorder.Code)
+	})
+}
+
+func (s *importsSuite) TestShuttingDown() {
+	// given
+	s.SetupTest()
+	s.svc.blockCreating = true
+	var job ImportJob
+	s.Require().NoError(json.NewDecoder(s.startImport(`{"AEAJM": {}}`, jsonContentType).Body).Decode(&job))
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.Require().NoError(s.handler.Shutdown(ctx))
+
+	// then
+	s.Assert().Equal(ImportCanceled, s.getImport(job.ID).Status)
+	recorder := s.startImport(`{"AEAJM": {}}`, jsonContentType)
+	s.Assert().Equal(http.StatusServiceUnavailable, recorder.Code)
+}
+
+func (s *importsSuite) TestForgettingExpiredImports() {
+	// given
+	s.SetupTest()
+	var job ImportJob
+	s.Require().NoError(json.NewDecoder(s.startImport(`{"AEAJM": {}}`, jsonContentType).Body).Decode(&job))
+	s.awaitImport(job.ID)
+
+	// when
+	s.handler.importer.forgetExpired(time.Now().Add(finishedImportRetention + time.Minute))
+
+	// then
+	recorder := s.serve(httptest.NewRequest(http.MethodGet, "/imports/"+job.ID, nil))
+	s.Assert().Equal(http.StatusNotFound, recorder.Code)
+}
+
+func (s *importsSuite) startImport(body, contentType string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/imports", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", contentType)
+	return s.serve(req)
+}
+
+func (s *importsSuite) getImport(id string) ImportJob {
+	recorder := s.serve(httptest.NewRequest(http.MethodGet, "/imports/"+id, nil))
+	s.Require().Equal(http.StatusOK, recorder.Code)
+	var job ImportJob
+	s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&job))
+	return job
+}
+
+// awaitImport waits until the import isn't running anymore and returns its final state.
+func (s *importsSuite) awaitImport(id string) ImportJob {
+	var job ImportJob
+	s.Require().Eventually(func() bool {
+		job = s.getImport(id)
+		return job.Status != ImportRunning
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func (s *importsSuite) serve(req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.mux.ServeHTTP(recorder, req)
+	return recorder
+}
@@ -2,7 +2,11 @@ package webapp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 
 	"github.com/arturskrzydlo/ports/internal/common/pb"
 )
@@ -19,30 +23,394 @@ type Port struct {
 	Timezone    string    `json:"timezone"`
 	Unlocs      []string  `json:"unlocs"`
 	Code        string    `json:"code"`
+	// Location takes precedence over legacy Coordinates, responses have both of them set
+	Location *GeoPoint `json:"location,omitempty"`
 }
 
-func decodePort(decoder *json.Decoder) (*Port, error) {
+// GeoPoint is a location given by latitude and longitude in degrees.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// PortsPage is a single page of ports. NextPageToken is empty on the last page.
+type PortsPage struct {
+	Ports         []*Port `json:"ports"`
+	NextPageToken string  `json:"next_page_token"`
+}
+
+// SearchResults are ports matching search query, ordered from the most relevant.
+type SearchResults struct {
+	Ports []*Port `json:"ports"`
+}
+
+// NearbyQuery asks for at most K ports closest to the location, not farther than MaxDistanceKm
+// unless it's zero.
+type NearbyQuery struct {
+	Lat           float64
+	Lon           float64
+	K             int32
+	MaxDistanceKm float64
+}
+
+// NearbyPorts are ports ordered from the closest one.
+type NearbyPorts struct {
+	Ports []NearbyPort `json:"ports"`
+}
+
+// NearbyPort is a port along with its great-circle distance from the requested location.
+type NearbyPort struct {
+	Port       *Port   `json:"port"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// BoundingBox is an area between two latitudes and two longitudes. It crosses the antimeridian
+// when MinLon is greater than MaxLon.
+type BoundingBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+// PortsInBox are ports located within a bounding box, ordered by ID.
+type PortsInBox struct {
+	Ports []*Port `json:"ports"`
+}
+
+// OrderField is a field by which ports are ordered, named the same as in query string.
+type OrderField string
+
+const (
+	OrderByID            OrderField = "id"
+	OrderByName          OrderField = "name"
+	OrderByCountry       OrderField = "country"
+	OrderByInsertionTime OrderField = "insertion_time"
+)
+
+// PortsQuery describes which page of ports should be fetched and in what order. Ports with equal
+// values of OrderBy field are ordered by ID.
+type PortsQuery struct {
+	PageSize   int32
+	PageToken  string
+	OrderBy    OrderField
+	Descending bool
+	Filter     PortsFilter
+}
+
+// paged tells whether a single page of ports is requested rather than all of them.
+func (q PortsQuery) paged() bool {
+	return q.PageSize != 0 || q.PageToken != ""
+}
+
+// PortsFilter selects ports with given field values, compared ignoring case. Empty fields match
+// every port. Region and Unloc match ports having them among their regions and unlocs.
+type PortsFilter struct {
+	Country  string
+	Province string
+	City     string
+	Region   string
+	Timezone string
+	Code     string
+	Unloc    string
+}
+
+// ErrorPolicy decides what happens with ingested ports when some of them are rejected.
+type ErrorPolicy string
+
+const (
+	// SkipInvalid stores valid ports and reports rejected ones.
+	SkipInvalid ErrorPolicy = "skip_invalid"
+	// FailFast stops at the first rejected port, ports ingested before it stay stored.
+	FailFast ErrorPolicy = "fail_fast"
+	// AllOrNothing stores ports only when none of them is rejected.
+	AllOrNothing ErrorPolicy = "all_or_nothing"
+)
+
+// IngestMode decides what happens with stored ports which aren't ingested.
+type IngestMode string
+
+const (
+	// UpsertMode stores ingested ports, other stored ports are left as they are.
+	UpsertMode IngestMode = "upsert"
+	// ReplaceMode stores ingested ports and deletes stored ports which weren't ingested, except rejected ones.
+	ReplaceMode IngestMode = "replace"
+)
+
+// IngestOptions control how ingested ports are stored.
+type IngestOptions struct {
+	ErrorPolicy ErrorPolicy
+	Mode        IngestMode
+	// Atomic stores ports in a single transaction once all of them are received, so an ingestion which
+	// fails or is canceled halfway leaves stored ports unchanged. AllOrNothing policy and ReplaceMode
+	// are always atomic.
+	Atomic bool
+	// DryRun only reports which ports would be created, updated and deleted, nothing is changed
+	DryRun bool
+	// OnRejected is called from another goroutine with every port rejected by Ports service as soon as
+	// it's rejected, before the summary is returned. It may be nil.
+	OnRejected func(PortError)
+}
+
+// atomic tells whether ports are stored in a single transaction once all of them are received.
+func (o IngestOptions) atomic() bool {
+	return o.Atomic || o.ErrorPolicy == AllOrNothing || o.Mode == ReplaceMode || o.DryRun
+}
+
+// PortEntry is a port read from request body along with byte offset of its entry in the body.
+type PortEntry struct {
+	Port   *Port
+	Offset int64
+	// DecodeErr is set when the entry is valid json, but it isn't a port, i.e. its name is a number.
+	// Port has only ID set then and it's rejected.
+	DecodeErr error
+}
+
+// IngestSummary describes the outcome of storing a batch of ports.
+type IngestSummary struct {
+	Created    uint32      `json:"created"`
+	Updated    uint32      `json:"updated"`
+	Rejected   uint32      `json:"rejected"`
+	Errors     []PortError `json:"errors"`
+	CreatedIDs []string    `json:"created_ids"`
+	UpdatedIDs []string    `json:"updated_ids"`
+	// Aborted is set when ingestion was stopped by the error policy, ports listed as created and
+	// updated were stored before it was stopped
+	Aborted bool `json:"aborted"`
+	// Deleted ports are the ones removed by ReplaceMode
+	Deleted    uint32   `json:"deleted"`
+	DeletedIDs []string `json:"deleted_ids"`
+	// DryRun is set when nothing was changed, ports listed as created, updated and deleted would be
+	DryRun bool `json:"dry_run"`
+}
+
+// PortError explains why a port was rejected, Offset is byte offset of the port's entry in request body.
+type PortError struct {
+	PortID string `json:"port_id"`
+	Reason string `json:"reason"`
+	Offset int64  `json:"offset"`
+}
+
+// PortsDiff describes how uploaded ports differ from the stored ones.
+type PortsDiff struct {
+	// Ports lists new ports and ports which differ from the stored ones, in order of the upload
+	Ports     []PortDiff  `json:"ports"`
+	New       uint32      `json:"new"`
+	Changed   uint32      `json:"changed"`
+	Unchanged uint32      `json:"unchanged"`
+	Rejected  uint32      `json:"rejected"`
+	Errors    []PortError `json:"errors"`
+}
+
+type PortDiffStatus string
+
+const (
+	// PortNew isn't stored, all of its fields are added
+	PortNew     PortDiffStatus = "new"
+	PortChanged PortDiffStatus = "changed"
+)
+
+// PortDiff lists differences of fields of uploaded port, Offset is byte offset of the port's entry in request body.
+type PortDiff struct {
+	PortID string         `json:"port_id"`
+	Status PortDiffStatus `json:"status"`
+	Offset int64          `json:"offset"`
+	Fields []FieldDiff    `json:"fields"`
+}
+
+type FieldChange string
+
+const (
+	FieldAdded   FieldChange = "added"
+	FieldRemoved FieldChange = "removed"
+	FieldChanged FieldChange = "changed"
+)
+
+// FieldDiff is a difference of a single field, Old and New are values of the field in the stored and the
+// uploaded port. They're strings, lists of strings or locations, and they're nil when the field isn't set.
+type FieldDiff struct {
+	Field  string      `json:"field"`
+	Change FieldChange `json:"change"`
+	Old    any         `json:"old,omitempty"`
+	New    any         `json:"new,omitempty"`
+}
+
+// portIterator returns a function yielding consecutive ports decoded from the decoder.
+// It returns io.EOF when there are no more ports to read.
+func portIterator(decoder *json.Decoder) func() (*PortEntry, error) {
+	return func() (*PortEntry, error) {
+		for decoder.More() {
+			entry, err := decodePort(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if entry != nil {
+				return entry, nil
+			}
+		}
+		return nil, io.EOF
+	}
+}
+
+// ndjsonPortIterator returns a function yielding consecutive ports decoded from newline delimited json,
+// where every line is a single port with its id. It returns io.EOF when there are no more ports to read.
+func ndjsonPortIterator(decoder *json.Decoder) func() (*PortEntry, error) {
+	return func() (*PortEntry, error) {
+		// More skips whitespace before the port, so the offset points at its first byte
+		decoder.More()
+		entry := &PortEntry{Port: &Port{}, Offset: decoder.InputOffset()}
+		err := decoder.Decode(entry.Port)
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err = entryErr(entry, err); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	}
+}
+
+func decodePort(decoder *json.Decoder) (*PortEntry, error) {
+	offset := decoder.InputOffset()
 	token, decoderErr := decoder.Token()
 	if decoderErr != nil {
-		return nil, fmt.Errorf("failed to get token: %w", decoderErr)
+		return nil, &bodyError{offset: offset, err: fmt.Errorf("failed to get token: %w", decoderErr)}
 	}
 
-	var port *Port
-
 	switch typedToken := token.(type) {
 	case json.Delim:
 		// Do nothing for delimiters like "[" and "]"
+		return nil, nil
 	case string: // json should start with port id key which is string
-		decodeErr := decoder.Decode(&port)
-		if decodeErr != nil {
-			return nil, fmt.Errorf("failed to decode to port: %w", decodeErr)
+		// offset of the key, assuming it isn't escaped in the body
+		entry := &PortEntry{Port: &Port{}, Offset: decoder.InputOffset() - int64(len(strconv.Quote(typedToken)))}
+		if err := entryErr(entry, decoder.Decode(entry.Port)); err != nil {
+			return nil, err
 		}
-		port.ID = typedToken
+		entry.Port.ID = typedToken
+		return entry, nil
 	default:
-		return nil, fmt.Errorf("incorrect json token: %v", token)
+		return nil, &bodyError{offset: offset, err: fmt.Errorf("incorrect json token: %v", token)}
+	}
+}
+
+// entryErr sets DecodeErr of the entry when its json value doesn't fit into a port, the decoder can read
+// next ports then. Other errors, i.e. syntax errors, stop decoding and are returned as bodyError.
+func entryErr(entry *PortEntry, decodeErr error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(decodeErr, &typeErr) {
+		entry.DecodeErr = fmt.Errorf("failed to decode port: %w", decodeErr)
+		return nil
 	}
+	if decodeErr != nil {
+		return &bodyError{
+			offset: entry.Offset,
+			err:    fmt.Errorf("failed to decode port at offset %d: %w", entry.Offset, decodeErr),
+		}
+	}
+	return nil
+}
+
+// bodyError is returned by port iterators when request body can't be decoded any further, offset is byte
+// offset in the body of the entry which couldn't be read. It's ErrInvalidRequest.
+type bodyError struct {
+	offset int64
+	err    error
+}
 
-	return port, nil
+func (e *bodyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *bodyError) Unwrap() []error {
+	return []error{ErrInvalidRequest, e.err}
+}
+
+// decodePortBody decodes a single port sent in request body. ID of the port is taken from
+// the request path, so the body may omit it, but it can't point at a different port.
+func decodePortBody(body io.Reader, id string) (*Port, error) {
+	var port Port
+	if err := json.NewDecoder(body).Decode(&port); err != nil {
+		return nil, fmt.Errorf("failed to decode port: %w", err)
+	}
+	if port.ID != "" && port.ID != id {
+		return nil, fmt.Errorf("port id %q doesn't match id %q from path", port.ID, id)
+	}
+	port.ID = id
+	return &port, nil
+}
+
+// portPatch is JSON merge patch (RFC 7386) of a port, see decodeMergePatch.
+type portPatch struct {
+	// port has values from the patch
+	port *Port
+	// fields are sorted names of the fields present in the patch
+	fields []string
+	// hasLat and hasLon tell which members location object of the patch has, when it's set
+	hasLat, hasLon bool
+}
+
+// partialLocation tells whether location object of the patch lacks lat or lon, which have to be merged
+// into the stored location then.
+func (p *portPatch) partialLocation() bool {
+	return p.port.Location != nil && !(p.hasLat && p.hasLon)
+}
+
+// mergeLocation merges members of location object of the patch into the stored location, the way
+// RFC 7386 merges nested objects.
+func (p *portPatch) mergeLocation(stored *GeoPoint) error {
+	if stored == nil {
+		return errors.New("location has to have both lat and lon, as the port has no location to merge it into")
+	}
+	if !p.hasLat {
+		p.port.Location.Lat = stored.Lat
+	}
+	if !p.hasLon {
+		p.port.Location.Lon = stored.Lon
+	}
+	return nil
+}
+
+// decodeMergePatch decodes JSON merge patch (RFC 7386) of a port. Fields set to null are cleared.
+func decodeMergePatch(body io.Reader, id string) (*portPatch, error) {
+	rawPatch, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patch: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(rawPatch, &fields); err != nil {
+		return nil, fmt.Errorf("patch must be a json object: %w", err)
+	}
+	var port Port
+	if err = json.Unmarshal(rawPatch, &port); err != nil {
+		return nil, fmt.Errorf("failed to decode patch: %w", err)
+	}
+	if port.ID != "" && port.ID != id {
+		return nil, fmt.Errorf("port id %q doesn't match id %q from path", port.ID, id)
+	}
+	port.ID = id
+
+	patch := &portPatch{port: &port, fields: make([]string, 0, len(fields))}
+	if port.Location != nil {
+		// location decoded fine, so it's an object
+		var location map[string]json.RawMessage
+		_ = json.Unmarshal(fields["location"], &location)
+		for _, member := range []string{"lat", "lon"} {
+			if string(location[member]) == "null" {
+				return nil, fmt.Errorf("location.%s can't be removed, location is removed as a whole", member)
+			}
+		}
+		_, patch.hasLat = location["lat"]
+		_, patch.hasLon = location["lon"]
+	}
+	for field := range fields {
+		if field != "id" {
+			patch.fields = append(patch.fields, field)
+		}
+	}
+	sort.Strings(patch.fields)
+	return patch, nil
 }
 
 func portToPB(port *Port) *pb.Port {
@@ -58,6 +426,7 @@ func portToPB(port *Port) *pb.Port {
 		Timezone:    port.Timezone,
 		Unlocs:      port.Unlocs,
 		Code:        port.Code,
+		Location:    geoPointToPB(port.Location),
 	}
 }
 
@@ -74,5 +443,120 @@ func pbToPort(portPb *pb.Port) *Port {
 		Timezone:    portPb.Timezone,
 		Unlocs:      portPb.Unlocs,
 		Code:        portPb.Code,
+		Location:    pbToGeoPoint(portPb.Location),
+	}
+}
+
+func geoPointToPB(point *GeoPoint) *pb.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &pb.GeoPoint{Lat: point.Lat, Lon: point.Lon}
+}
+
+func pbToGeoPoint(pointPb *pb.GeoPoint) *GeoPoint {
+	if pointPb == nil {
+		return nil
+	}
+	return &GeoPoint{Lat: pointPb.Lat, Lon: pointPb.Lon}
+}
+
+func pbToPortErrors(errorsPb []*pb.PortError) []PortError {
+	portErrors := make([]PortError, len(errorsPb))
+	for i, portErr := range errorsPb {
+		portErrors[i] = pbToPortError(portErr)
+	}
+	return portErrors
+}
+
+func pbToPortError(portErr *pb.PortError) PortError {
+	return PortError{
+		PortID: portErr.GetPortId(),
+		Reason: portErr.GetReason(),
+		Offset: portErr.GetOffset(),
+	}
+}
+
+func pbToIngestSummary(summaryPb *pb.IngestSummary) *IngestSummary {
+	return &IngestSummary{
+		Created:    summaryPb.Created,
+		Updated:    summaryPb.Updated,
+		Rejected:   summaryPb.Rejected,
+		Errors:     pbToPortErrors(summaryPb.Errors),
+		CreatedIDs: append([]string{}, summaryPb.CreatedIds...),
+		UpdatedIDs: append([]string{}, summaryPb.UpdatedIds...),
+		Aborted:    summaryPb.Aborted,
+		Deleted:    summaryPb.Deleted,
+		DeletedIDs: append([]string{}, summaryPb.DeletedIds...),
+		DryRun:     summaryPb.DryRun,
+	}
+}
+
+func pbToPortsDiff(diffPb *pb.PortsDiff) *PortsDiff {
+	diff := &PortsDiff{
+		Ports:     make([]PortDiff, len(diffPb.Ports)),
+		New:       diffPb.New,
+		Changed:   diffPb.Changed,
+		Unchanged: diffPb.Unchanged,
+		Rejected:  diffPb.Rejected,
+		Errors:    pbToPortErrors(diffPb.Errors),
+	}
+	for i, portDiff := range diffPb.Ports {
+		fields := make([]FieldDiff, len(portDiff.Fields))
+		for j, field := range portDiff.Fields {
+			fields[j] = FieldDiff{
+				Field:  field.Field,
+				Change: fieldChanges[field.Change],
+				Old:    field.OldValue.AsInterface(),
+				New:    field.NewValue.AsInterface(),
+			}
+		}
+		diff.Ports[i] = PortDiff{
+			PortID: portDiff.PortId,
+			Status: portDiffStatuses[portDiff.Status],
+			Offset: portDiff.Offset,
+			Fields: fields,
+		}
+	}
+	return diff
+}
+
+var portDiffStatuses = map[pb.PortDiffStatus]PortDiffStatus{
+	pb.PortDiffStatus_PORT_DIFF_STATUS_NEW:     PortNew,
+	pb.PortDiffStatus_PORT_DIFF_STATUS_CHANGED: PortChanged,
+}
+
+var fieldChanges = map[pb.FieldChange]FieldChange{
+	pb.FieldChange_FIELD_CHANGE_ADDED:   FieldAdded,
+	pb.FieldChange_FIELD_CHANGE_REMOVED: FieldRemoved,
+	pb.FieldChange_FIELD_CHANGE_CHANGED: FieldChanged,
+}
+
+var errorPoliciesPB = map[ErrorPolicy]pb.ErrorPolicy{
+	SkipInvalid:  pb.ErrorPolicy_ERROR_POLICY_SKIP_INVALID,
+	FailFast:     pb.ErrorPolicy_ERROR_POLICY_FAIL_FAST,
+	AllOrNothing: pb.ErrorPolicy_ERROR_POLICY_ALL_OR_NOTHING,
+}
+
+var orderFieldsPB = map[OrderField]pb.PortOrderField{
+	OrderByID:            pb.PortOrderField_PORT_ORDER_FIELD_ID,
+	OrderByName:          pb.PortOrderField_PORT_ORDER_FIELD_NAME,
+	OrderByCountry:       pb.PortOrderField_PORT_ORDER_FIELD_COUNTRY,
+	OrderByInsertionTime: pb.PortOrderField_PORT_ORDER_FIELD_INSERTION_TIME,
+}
+
+func orderToPB(query PortsQuery) *pb.PortOrder {
+	return &pb.PortOrder{Field: orderFieldsPB[query.OrderBy], Descending: query.Descending}
+}
+
+func filterToPB(filter PortsFilter) *pb.PortFilter {
+	return &pb.PortFilter{
+		Country:  filter.Country,
+		Province: filter.Province,
+		City:     filter.City,
+		Region:   filter.Region,
+		Timezone: filter.Timezone,
+		Code:     filter.Code,
+		Unloc:    filter.Unloc,
 	}
 }
@@ -0,0 +1,211 @@
+package webapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const jsonContentType = "application/json"
+
+var (
+	// ErrMethodNotAllowed is returned when the endpoint doesn't handle the request method.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	// ErrUnsupportedMediaType is returned when request body is sent in a format the endpoint doesn't read.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrNotAcceptable is returned when none of the media types accepted by the client can be produced.
+	ErrNotAcceptable = errors.New("not acceptable")
+)
+
+// response is a result of a handler, it's written by the router once the handler returns.
+type response struct {
+	status int
+	// body is encoded as json, nothing is written when it's nil
+	body any
+	// stream writes the body of contentType instead of json encoded body. The status is sent before it's
+	// called, so its error can't change the response anymore and it's only logged.
+	stream      func(w io.Writer) error
+	contentType string
+	// header is added to headers of the response
+	header http.Header
+}
+
+// mediaTypeKey is a context key of the media type of the response, negotiated by the router.
+type mediaTypeKey struct{}
+
+// handlerFunc handles a request with a single method. Returned error is rendered as error response,
+// so handlers never write to http.ResponseWriter on their own.
+type handlerFunc func(request *http.Request) (*response, error)
+
+// methodHandlers route requests to an endpoint by their methods.
+type methodHandlers map[string]handlerFunc
+
+// allowed returns the methods handled by the endpoint, as listed in Allow header.
+func (m methodHandlers) allowed() string {
+	methods := make([]string, 0, len(m))
+	for method := range m {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// route passes the request to the handler of its method and writes its response. It's the only place
+// writing responses, so every request gets exactly one status code and one body. Endpoints respond with
+// json unless they produce other media types, the first of them is the default one. The media type
+// negotiated with Accept header is available to the handler by responseMediaType.
+func (sh *ServiceHandler) route(
+	respWriter http.ResponseWriter, request *http.Request, handlers methodHandlers, produces ...string,
+) {
+	handler, ok := handlers[request.Method]
+	if !ok {
+		respWriter.Header().Set("Allow", handlers.allowed())
+		sh.writeErr(respWriter, fmt.Errorf("%w: %s", ErrMethodNotAllowed, request.Method))
+		return
+	}
+	if len(produces) == 0 {
+		produces = []string{jsonContentType}
+	}
+	mediaType, ok := negotiate(request.Header.Get("Accept"), produces...)
+	if !ok {
+		sh.writeErr(respWriter, fmt.Errorf("%w: only %s responses are available",
+			ErrNotAcceptable, strings.Join(produces, ", ")))
+		return
+	}
+
+	resp, err := handler(request.WithContext(context.WithValue(request.Context(), mediaTypeKey{}, mediaType)))
+	if err != nil {
+		sh.writeErr(respWriter, err)
+		return
+	}
+	sh.write(respWriter, resp)
+}
+
+// responseMediaType returns media type of the response negotiated by the router.
+func responseMediaType(request *http.Request) string {
+	mediaType, _ := request.Context().Value(mediaTypeKey{}).(string)
+	return mediaType
+}
+
+// negotiate picks the offered media type preferred by Accept header, following its quality values.
+// Offers are tried in order when the client prefers them equally. Missing header accepts anything.
+func negotiate(accept string, offered ...string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offered[0], true
+	}
+
+	var (
+		best        string
+		bestQuality float64
+	)
+	for _, offer := range offered {
+		if quality := acceptQuality(accept, offer); quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// acceptQuality returns quality value of the most specific media range of Accept header matching
+// the media type, or zero when it isn't accepted.
+func acceptQuality(accept, mediaType string) float64 {
+	var (
+		quality     float64
+		specificity = -1
+	)
+	for _, mediaRange := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		rangeSpecificity := mediaRangeSpecificity(rangeType, mediaType)
+		if rangeSpecificity <= specificity {
+			continue
+		}
+		specificity = rangeSpecificity
+		quality = 1
+		if rawQuality, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(rawQuality, 64); err != nil {
+				quality = 0
+			}
+		}
+	}
+	return quality
+}
+
+// mediaRangeSpecificity returns -1 when media range doesn't match the media type, otherwise the
+// more specific the range is, the higher is the result.
+func mediaRangeSpecificity(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") &&
+		strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	default:
+		return -1
+	}
+}
+
+// invalidRequestErr marks error of decoding or parsing a request, so it's rendered with 400.
+func invalidRequestErr(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+}
+
+// writeErr writes error response with status code matching the error.
+func (sh *ServiceHandler) writeErr(respWriter http.ResponseWriter, err error) {
+	statusCode := errorStatusCode(err)
+	if statusCode == http.StatusInternalServerError {
+		sh.log.Error("failed to handle request", zap.Error(err))
+	}
+	resp := errorResp{Error: err.Error(), Code: errorCodes[statusCode]}
+	var svcErr *ServiceError
+	if errors.As(err, &svcErr) {
+		resp.FieldViolations = svcErr.FieldViolations
+	}
+	sh.write(respWriter, &response{status: statusCode, body: resp})
+}
+
+func (sh *ServiceHandler) write(respWriter http.ResponseWriter, resp *response) {
+	for name, values := range resp.header {
+		for _, value := range values {
+			respWriter.Header().Add(name, value)
+		}
+	}
+	if resp.stream != nil {
+		respWriter.Header().Set("Content-Type", resp.contentType)
+		respWriter.WriteHeader(resp.status)
+		if err := resp.stream(respWriter); err != nil {
+			sh.log.Warn("failed to stream response", zap.Error(err))
+		}
+		return
+	}
+	if resp.body == nil {
+		respWriter.WriteHeader(resp.status)
+		return
+	}
+
+	content, err := json.Marshal(resp.body)
+	if err != nil {
+		sh.log.Warn("failed to marshal response", zap.Error(err))
+		respWriter.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	respWriter.Header().Set("Content-Type", jsonContentType)
+	respWriter.WriteHeader(resp.status)
+	if _, err = respWriter.Write(content); err != nil {
+		sh.log.Warn("failed to send response", zap.Error(err))
+	}
+}
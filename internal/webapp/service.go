@@ -4,31 +4,82 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	pb2 "github.com/arturskrzydlo/ports/internal/common/pb"
 )
 
 const (
 	portsEndpointName = "ports"
-	maxPartSizeInMB   = 10
-	mbShift           = 20
+	// multipartContentType is a media type of form with uploaded ports file
+	multipartContentType = "multipart/form-data"
+	// ndjsonContentType is a media type of newline delimited json, a stream of json values each in its own line
+	ndjsonContentType = "application/x-ndjson"
+	// mergePatchContentType is a media type of JSON merge patch defined by RFC 7386
+	mergePatchContentType = "application/merge-patch+json"
+)
+
+var (
+	// ErrPortNotFound is returned when requested port doesn't exist.
+	ErrPortNotFound = errors.New("port not found")
+	// ErrInvalidPort is returned when Ports service refuses to store a port because it's invalid.
+	ErrInvalidPort = errors.New("invalid port")
+	// ErrInvalidQuery is returned when Ports service refuses to run a query because it's invalid.
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrInvalidRequest is returned when request can't be decoded, i.e. uploaded ports aren't valid json.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrPortAlreadyExists is returned when creating a port with ID which is already taken.
+	ErrPortAlreadyExists = errors.New("port already exists")
+	// ErrServiceUnavailable is returned when Ports service or its storage can't serve requests at the
+	// moment, so the request may succeed when it's retried.
+	ErrServiceUnavailable = errors.New("ports service unavailable")
 )
 
 type PortsService interface {
-	CreatePort(ctx context.Context, port *Port) error
-	FetchPorts(ctx context.Context) ([]*Port, error)
+	// CreatePorts stores ports returned by nextPort, rejected ports are handled according to the options.
+	// Body which can't be decoded any further aborts ingestion, which is reported in the summary.
+	CreatePorts(ctx context.Context, opts IngestOptions, nextPort func() (*PortEntry, error)) (*IngestSummary, error)
+	// DiffPorts compares ports returned by nextPort to the stored ones without changing anything.
+	DiffPorts(ctx context.Context, nextPort func() (*PortEntry, error)) (*PortsDiff, error)
+	UpdatePort(ctx context.Context, port *Port) error
+	// PatchPort updates only listed fields of the port.
+	PatchPort(ctx context.Context, port *Port, fields []string) error
+	DeletePort(ctx context.Context, id string) error
+	FetchPort(ctx context.Context, id string) (*Port, error)
+	// FetchPorts returns a page of ports, all ports are returned when neither page size nor
+	// page token is set.
+	FetchPorts(ctx context.Context, query PortsQuery) (*PortsPage, error)
+	// StreamPorts returns a function yielding all ports matching the query one by one, in order of the query,
+	// paging of the query is ignored. The function returns io.EOF when there are no more ports.
+	StreamPorts(ctx context.Context, query PortsQuery) (func() (*Port, error), error)
+	// SearchPorts returns at most limit ports matching the text query, the most relevant first.
+	SearchPorts(ctx context.Context, query string, limit int32) (*SearchResults, error)
+	FindNearestPorts(ctx context.Context, query NearbyQuery) (*NearbyPorts, error)
+	// FindPortsInBoundingBox returns at most limit ports within the box.
+	FindPortsInBoundingBox(ctx context.Context, box BoundingBox, limit int32) (*PortsInBox, error)
 }
 
 type ServiceHandler struct {
 	svc        PortsService
+	importer   *importer
 	httpServer *http.Server
-	log        *zap.Logger
+	// uploadTimeout limits reading of uploaded ports and writing of the response to them instead of
+	// timeouts of httpServer, zero means there is no limit
+	uploadTimeout time.Duration
+	log           *zap.Logger
 }
 
 type Service struct {
@@ -36,21 +87,29 @@ type Service struct {
 	portsClient pb2.PortServiceClient
 }
 
-type errorResp struct {
-	Error string `json:"error_message"`
-}
-
-func NewServiceHandler(svc PortsService, httpServer *http.Server, logger *zap.Logger) *ServiceHandler {
+func NewServiceHandler(svc PortsService, httpServer *http.Server, uploadTimeout time.Duration, maxImportSize int64,
+	logger *zap.Logger,
+) *ServiceHandler {
 	return &ServiceHandler{
-		svc:        svc,
-		httpServer: httpServer,
-		log:        logger,
+		svc:           svc,
+		importer:      newImporter(svc, os.TempDir(), maxImportSize, logger),
+		httpServer:    httpServer,
+		uploadTimeout: uploadTimeout,
+		log:           logger,
 	}
 }
 
 // Register connects the handlers to the router.
 func (sh *ServiceHandler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("/"+portsEndpointName, sh.ports)
+	mux.HandleFunc("/"+portsEndpointName+"/", sh.port)
+	mux.HandleFunc("/"+portsEndpointName+"/search", sh.searchPorts)
+	mux.HandleFunc("/"+portsEndpointName+"/nearby", sh.nearbyPorts)
+	mux.HandleFunc("/"+portsEndpointName+"/within", sh.portsWithin)
+	mux.HandleFunc("/"+portsEndpointName+"/diff", sh.portsDiff)
+	mux.HandleFunc("/"+portsEndpointName+"/export", sh.exportPorts)
+	mux.HandleFunc("/"+importsEndpointName, sh.imports)
+	mux.HandleFunc("/"+importsEndpointName+"/", sh.importJob)
 }
 
 func (sh *ServiceHandler) Run() {
@@ -59,107 +118,791 @@ func (sh *ServiceHandler) Run() {
 	}
 }
 
+// allowUpload replaces read and write deadlines of the request, which are set by ReadTimeout and WriteTimeout
+// of the server, with uploadTimeout, so uploads of huge ports files aren't cut off halfway.
+func (sh *ServiceHandler) allowUpload(respWriter http.ResponseWriter) {
+	var deadline time.Time
+	if sh.uploadTimeout > 0 {
+		deadline = time.Now().Add(sh.uploadTimeout)
+	}
+	controller := http.NewResponseController(respWriter)
+	err := errors.Join(controller.SetReadDeadline(deadline), controller.SetWriteDeadline(deadline))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		sh.log.Warn("failed to set deadlines of upload", zap.Error(err))
+	}
+}
+
+// Shutdown cancels imports running in background and gracefully shuts down the server.
+func (sh *ServiceHandler) Shutdown(ctx context.Context) error {
+	importsErr := sh.importer.close(ctx)
+	return errors.Join(importsErr, sh.httpServer.Shutdown(ctx))
+}
+
 func (sh *ServiceHandler) ports(respWriter http.ResponseWriter, request *http.Request) {
+	// ports are listed as csv as well, whereas summary of uploaded ports is always json
+	produces := []string{jsonContentType}
+	switch request.Method {
+	case http.MethodGet:
+		produces = append(produces, csvContentType)
+	case http.MethodPost:
+		sh.allowUpload(respWriter)
+	}
+	sh.route(respWriter, request, methodHandlers{
+		http.MethodGet:  sh.listPorts,
+		http.MethodPost: sh.ingestPorts,
+	}, produces...)
+}
+
+// port handles requests to a single port identified by the last segment of the path, i.e. /ports/AEAJM
+func (sh *ServiceHandler) port(respWriter http.ResponseWriter, request *http.Request) {
+	id := portID(request)
+	if id == "" || strings.Contains(id, "/") {
+		sh.writeErr(respWriter, ErrPortNotFound)
+		return
+	}
+
+	sh.route(respWriter, request, methodHandlers{
+		http.MethodGet:    sh.getPort,
+		http.MethodPut:    sh.updatePort,
+		http.MethodPatch:  sh.patchPort,
+		http.MethodDelete: sh.deletePort,
+	})
+}
+
+// searchPorts handles type-ahead search, i.e. /ports/search?q=abu+dh&limit=5
+func (sh *ServiceHandler) searchPorts(respWriter http.ResponseWriter, request *http.Request) {
+	sh.route(respWriter, request, methodHandlers{http.MethodGet: sh.search})
+}
+
+// nearbyPorts handles /ports/nearby?lat=25.4&lon=55.5&k=5&max_distance_km=100
+func (sh *ServiceHandler) nearbyPorts(respWriter http.ResponseWriter, request *http.Request) {
+	sh.route(respWriter, request, methodHandlers{http.MethodGet: sh.findNearestPorts})
+}
+
+// portsWithin handles /ports/within?min_lat=24&min_lon=54&max_lat=26&max_lon=56&limit=10
+func (sh *ServiceHandler) portsWithin(respWriter http.ResponseWriter, request *http.Request) {
+	sh.route(respWriter, request, methodHandlers{http.MethodGet: sh.findPortsInBoundingBox})
+}
+
+// portsDiff handles comparison of uploaded ports to the stored ones, it takes the same bodies as POST /ports
+func (sh *ServiceHandler) portsDiff(respWriter http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodPost {
+		sh.allowUpload(respWriter)
+	}
+	sh.route(respWriter, request, methodHandlers{http.MethodPost: sh.diffPorts})
+}
+
+// portID returns id of the port from the last segment of the path.
+func portID(request *http.Request) string {
+	return strings.TrimPrefix(request.URL.Path, "/"+portsEndpointName+"/")
+}
+
+// listPorts responds with a page of ports, or with all of them when csv is accepted, as spreadsheets
+// aren't paged.
+func (sh *ServiceHandler) listPorts(request *http.Request) (*response, error) {
+	query, err := parsePortsQuery(request.URL.Query())
+	if err != nil {
+		return nil, invalidRequestErr(err)
+	}
+	if responseMediaType(request) == csvContentType {
+		if query.paged() {
+			return nil, invalidRequestErr(errors.New("csv isn't paged, page_size and page_token can't be set"))
+		}
+		return sh.streamPorts(request, query, csvContentType)
+	}
+	page, err := sh.svc.FetchPorts(request.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+	if !query.paged() {
+		// unpaged requests get all ports as a plain json array, the way they always did
+		return &response{status: http.StatusOK, body: page.Ports}, nil
+	}
+	return &response{status: http.StatusOK, body: page}, nil
+}
+
+func (sh *ServiceHandler) getPort(request *http.Request) (*response, error) {
+	port, err := sh.svc.FetchPort(request.Context(), portID(request))
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: port}, nil
+}
+
+func (sh *ServiceHandler) updatePort(request *http.Request) (*response, error) {
+	port, err := decodePortBody(request.Body, portID(request))
+	if err != nil {
+		return nil, invalidRequestErr(err)
+	}
+	if err = sh.svc.UpdatePort(request.Context(), port); err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: port}, nil
+}
+
+func (sh *ServiceHandler) patchPort(request *http.Request) (*response, error) {
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if mediaType != mergePatchContentType && mediaType != jsonContentType {
+		return nil, fmt.Errorf("%w: patch must be sent as %s", ErrUnsupportedMediaType, mergePatchContentType)
+	}
+
+	id := portID(request)
+	patch, err := decodeMergePatch(request.Body, id)
+	if err != nil {
+		return nil, invalidRequestErr(err)
+	}
+	if patch.partialLocation() {
+		stored, fetchErr := sh.svc.FetchPort(request.Context(), id)
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		if err = patch.mergeLocation(stored.Location); err != nil {
+			return nil, invalidRequestErr(err)
+		}
+	}
+	// empty patch changes nothing, whereas update without fields would replace the whole port
+	if len(patch.fields) > 0 {
+		if err = sh.svc.PatchPort(request.Context(), patch.port, patch.fields); err != nil {
+			return nil, err
+		}
+	}
+
+	port, err := sh.svc.FetchPort(request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: port}, nil
+}
+
+func (sh *ServiceHandler) deletePort(request *http.Request) (*response, error) {
+	if err := sh.svc.DeletePort(request.Context(), portID(request)); err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusNoContent}, nil
+}
+
+func (sh *ServiceHandler) search(request *http.Request) (*response, error) {
+	query := request.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		return nil, invalidRequestErr(errors.New("q query param is required"))
+	}
+	limit, err := parseCountParam(request.URL.Query(), "limit")
+	if err != nil {
+		return nil, invalidRequestErr(err)
+	}
+
+	results, err := sh.svc.SearchPorts(request.Context(), query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: results}, nil
+}
+
+func (sh *ServiceHandler) findNearestPorts(request *http.Request) (*response, error) {
+	var (
+		values = request.URL.Query()
+		query  NearbyQuery
+		errs   = make([]error, 4)
+	)
+	query.Lat, errs[0] = parseFloatParam(values, "lat", true)
+	query.Lon, errs[1] = parseFloatParam(values, "lon", true)
+	query.K, errs[2] = parseCountParam(values, "k")
+	query.MaxDistanceKm, errs[3] = parseFloatParam(values, "max_distance_km", false)
+	if err := errors.Join(errs...); err != nil {
+		return nil, invalidRequestErr(err)
+	}
+
+	nearby, err := sh.svc.FindNearestPorts(request.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: nearby}, nil
+}
+
+func (sh *ServiceHandler) findPortsInBoundingBox(request *http.Request) (*response, error) {
 	var (
-		err        error
-		response   any
-		statusCode int
+		values = request.URL.Query()
+		box    BoundingBox
+		limit  int32
+		errs   = make([]error, 5)
 	)
+	box.MinLat, errs[0] = parseFloatParam(values, "min_lat", true)
+	box.MinLon, errs[1] = parseFloatParam(values, "min_lon", true)
+	box.MaxLat, errs[2] = parseFloatParam(values, "max_lat", true)
+	box.MaxLon, errs[3] = parseFloatParam(values, "max_lon", true)
+	limit, errs[4] = parseCountParam(values, "limit")
+	if err := errors.Join(errs...); err != nil {
+		return nil, invalidRequestErr(err)
+	}
 
-	switch request.Method {
-	case http.MethodPost:
-		response, err = sh.ingestPorts(request)
-		statusCode = http.StatusCreated
-	case http.MethodGet:
-		response, err = sh.svc.FetchPorts(request.Context())
-		statusCode = http.StatusOK
-	default:
-		http.Error(respWriter, "Method not allowed", http.StatusMethodNotAllowed)
+	ports, err := sh.svc.FindPortsInBoundingBox(request.Context(), box, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: http.StatusOK, body: ports}, nil
+}
+
+// ingestPorts creates ports sent either as a file uploaded in multipart form, as a json object keyed by port ids
+// or as newline delimited json. Ports are decoded from the body while they're sent to Ports service, so the body
+// is never buffered and its size isn't limited.
+func (sh *ServiceHandler) ingestPorts(request *http.Request) (*response, error) {
+	body, mediaType, err := portsBody(request)
+	if err != nil {
+		return nil, err
 	}
 
-	// here could be various reasons which should be mapped to correct status code
-	// custom error would be solution here - this way is just to speed up and keep it simple
+	opts, err := parseIngestOptions(request.URL.Query())
 	if err != nil {
-		sh.renderErr(respWriter, err.Error(), http.StatusInternalServerError)
+		return nil, invalidRequestErr(err)
 	}
 
-	sh.renderResponse(respWriter, response, statusCode)
+	summary, err := sh.svc.CreatePorts(request.Context(), opts, decodePorts(body, mediaType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ports: %w", err)
+	}
+	switch {
+	case summary.Aborted:
+		return &response{status: http.StatusUnprocessableEntity, body: summary}, nil
+	case summary.DryRun:
+		return &response{status: http.StatusOK, body: summary}, nil
+	default:
+		return &response{status: http.StatusCreated, body: summary}, nil
+	}
 }
 
-func (sh *ServiceHandler) ingestPorts(request *http.Request) (createdPortIDs []string, err error) {
-	// Get the JSON file from the request body (max part size is 10MB)
-	err = request.ParseMultipartForm(maxPartSizeInMB << mbShift)
+// diffPorts compares ports from the body to the stored ones, ports are decoded while they're sent to
+// Ports service the same way as by ingestPorts.
+func (sh *ServiceHandler) diffPorts(request *http.Request) (*response, error) {
+	body, mediaType, err := portsBody(request)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := sh.svc.DiffPorts(request.Context(), decodePorts(body, mediaType))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse multipart form: %w", err)
+		return nil, fmt.Errorf("failed to compare ports: %w", err)
 	}
-	file, _, fileErr := request.FormFile("ports")
-	if fileErr != nil {
-		return nil, fmt.Errorf("failed to read part from multipart form: %w", err)
+	return &response{status: http.StatusOK, body: diff}, nil
+}
+
+// portsBody returns reader of ports sent in the request along with their media type, which is either
+// json, newline delimited json or csv. Ports uploaded in multipart form are json, unless their part is csv.
+// csv sent with format=unlocode query param is UN/LOCODE code list, its media type is unlocodeFormat then.
+func portsBody(request *http.Request) (io.Reader, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	var body io.Reader
+	switch mediaType {
+	case multipartContentType:
+		file, fileMediaType, err := portsFilePart(request)
+		if err != nil {
+			return nil, "", invalidRequestErr(err)
+		}
+		body, mediaType = file, fileMediaType
+	case jsonContentType, ndjsonContentType, csvContentType:
+		body = request.Body
+	default:
+		return nil, "", fmt.Errorf("%w: ports must be sent as %s, %s, %s or %s", ErrUnsupportedMediaType,
+			jsonContentType, ndjsonContentType, csvContentType, multipartContentType)
+	}
+
+	switch format := request.URL.Query().Get("format"); format {
+	case "":
+		return body, mediaType, nil
+	case unlocodeFormat:
+		if mediaType != csvContentType {
+			return nil, "", fmt.Errorf("%w: UN/LOCODE code list must be sent as %s", ErrUnsupportedMediaType,
+				csvContentType)
+		}
+		return body, unlocodeFormat, nil
+	default:
+		return nil, "", invalidRequestErr(fmt.Errorf("format must be unlocode when it's set, got %q", format))
 	}
-	defer file.Close()
+}
 
-	fileReader := bufio.NewReader(file)
-	decoder := json.NewDecoder(fileReader)
-	createdPortIDs = make([]string, 0)
+// decodePorts returns a function yielding consecutive ports read from json, newline delimited json, csv or
+// UN/LOCODE code list.
+func decodePorts(body io.Reader, mediaType string) func() (*PortEntry, error) {
+	switch mediaType {
+	case unlocodeFormat:
+		return unlocodePorts(body, nil)
+	case csvContentType:
+		return csvPortIterator(body)
+	case ndjsonContentType:
+		return ndjsonPortIterator(json.NewDecoder(bufio.NewReader(body)))
+	default:
+		return portIterator(json.NewDecoder(bufio.NewReader(body)))
+	}
+}
 
-	for decoder.More() {
-		port, decodeErr := decodePort(decoder)
-		if decodeErr != nil {
-			return createdPortIDs, decodeErr
+// portsFilePart returns ports part of multipart form along with its media type, which is csv when the part
+// is sent as csv and json otherwise. Parts are read as a stream, so the file isn't buffered in memory nor
+// in temporary file.
+func portsFilePart(request *http.Request) (io.Reader, string, error) {
+	reader, err := request.MultipartReader()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse multipart form: %w", err)
+	}
+	for {
+		part, partErr := reader.NextPart()
+		if errors.Is(partErr, io.EOF) {
+			return nil, "", errors.New("ports part is missing in multipart form")
+		}
+		if partErr != nil {
+			return nil, "", fmt.Errorf("failed to read part from multipart form: %w", partErr)
 		}
-		if port != nil {
-			err = sh.svc.CreatePort(request.Context(), port)
-			if err != nil {
-				return createdPortIDs, fmt.Errorf("failed to create port %v, errMsg=%w", port, err)
+		if part.FormName() == "ports" {
+			if mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type")); mediaType == csvContentType {
+				return part, csvContentType, nil
 			}
-			createdPortIDs = append(createdPortIDs, port.ID)
+			return part, jsonContentType, nil
+		}
+	}
+}
+
+// parseFloatParam reads a number from the query param, which is zero when it's missing
+// and not required.
+func parseFloatParam(values url.Values, name string, required bool) (float64, error) {
+	rawValue := values.Get(name)
+	if rawValue == "" {
+		if required {
+			return 0, fmt.Errorf("%s query param is required", name)
+		}
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, got %q", name, rawValue)
+	}
+	return value, nil
+}
+
+// parseCountParam reads a non-negative integer from the query param, which is zero when it's missing.
+func parseCountParam(values url.Values, name string) (int32, error) {
+	rawValue := values.Get(name)
+	if rawValue == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseInt(rawValue, 10, 32)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", name, rawValue)
+	}
+	return int32(value), nil
+}
+
+// parseBoolParam reads a boolean from the query param, which is false when it's missing.
+func parseBoolParam(values url.Values, name string) (bool, error) {
+	rawValue := values.Get(name)
+	if rawValue == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(rawValue)
+	if err != nil {
+		return false, fmt.Errorf("%s must be either true or false, got %q", name, rawValue)
+	}
+	return value, nil
+}
+
+// parseIngestOptions reads error policy of ingestion, which is SkipInvalid by default, its mode, which
+// is UpsertMode by default, and whether it's atomic or dry run, which it isn't by default.
+func parseIngestOptions(values url.Values) (IngestOptions, error) {
+	opts := IngestOptions{
+		ErrorPolicy: ErrorPolicy(values.Get("error_policy")),
+		Mode:        IngestMode(values.Get("mode")),
+	}
+	if opts.ErrorPolicy == "" {
+		opts.ErrorPolicy = SkipInvalid
+	}
+	if _, ok := errorPoliciesPB[opts.ErrorPolicy]; !ok {
+		return IngestOptions{}, fmt.Errorf(
+			"error_policy must be one of skip_invalid, fail_fast or all_or_nothing, got %q", opts.ErrorPolicy)
+	}
+	switch opts.Mode {
+	case "":
+		opts.Mode = UpsertMode
+	case UpsertMode, ReplaceMode:
+	default:
+		return IngestOptions{}, fmt.Errorf("mode must be either upsert or replace, got %q", opts.Mode)
+	}
+
+	var err error
+	if opts.Atomic, err = parseBoolParam(values, "atomic"); err != nil {
+		return IngestOptions{}, err
+	}
+	if opts.DryRun, err = parseBoolParam(values, "dry_run"); err != nil {
+		return IngestOptions{}, err
+	}
+	return opts, nil
+}
+
+// parsePortsQuery reads paging, order and filter query parameters.
+func parsePortsQuery(values url.Values) (PortsQuery, error) {
+	query := PortsQuery{
+		PageToken: values.Get("page_token"),
+		OrderBy:   OrderByID,
+		Filter: PortsFilter{
+			Country:  values.Get("country"),
+			Province: values.Get("province"),
+			City:     values.Get("city"),
+			Region:   values.Get("region"),
+			Timezone: values.Get("timezone"),
+			Code:     values.Get("code"),
+			Unloc:    values.Get("unloc"),
+		},
+	}
+	pageSize, err := parseCountParam(values, "page_size")
+	if err != nil {
+		return PortsQuery{}, err
+	}
+	query.PageSize = pageSize
+	if orderBy := values.Get("order_by"); orderBy != "" {
+		query.OrderBy = OrderField(orderBy)
+		if _, ok := orderFieldsPB[query.OrderBy]; !ok {
+			return PortsQuery{}, fmt.Errorf("order_by must be one of id, name, country or insertion_time, got %q", orderBy)
 		}
 	}
-	return createdPortIDs, nil
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return PortsQuery{}, fmt.Errorf("order must be either asc or desc, got %q", order)
+	}
+	return query, nil
 }
 
 func NewService(logger *zap.Logger, portsClient pb2.PortServiceClient) *Service {
 	return &Service{log: logger, portsClient: portsClient}
 }
 
-func (s Service) CreatePort(ctx context.Context, port *Port) error {
-	_, err := s.portsClient.CreatePort(ctx, &pb2.CreatePortRequest{Port: portToPB(port)})
+// CreatePorts streams all ports returned by nextPort to the Ports service until nextPort
+// returns io.EOF. Ports rejected by Ports service are passed to opts.OnRejected as soon as they're reported.
+// Any other error returned by nextPort aborts the stream, but when it's a body which can't be decoded any
+// further, the aborted summary is returned (see abortIngestion). Entries which couldn't be decoded are sent
+// too, so Ports service rejects them following the error policy. Canceling ctx stops sending ports: atomic
+// ingestion is canceled, whereas non-atomic one is closed and the summary of ports stored before is returned
+// along with the error of ctx.
+func (s Service) CreatePorts(ctx context.Context, opts IngestOptions, nextPort func() (*PortEntry, error),
+) (*IngestSummary, error) {
+	// non-atomic stream outlives ctx, so ports stored before ctx is canceled are reported in the summary
+	streamCtx := ctx
+	if !opts.atomic() {
+		streamCtx = detachedContext{ctx}
+	}
+	streamCtx, cancel := context.WithCancel(streamCtx)
+	defer cancel()
+
+	stream, err := s.portsClient.StreamCreatePorts(streamCtx)
 	if err != nil {
-		return fmt.Errorf("failed to Create ports in Ports service:%w", err)
+		return nil, serviceErr("failed to open ports stream to Ports service", err, ErrInvalidPort)
 	}
+	// responses are received while ports are sent, so Ports service isn't blocked on reporting rejected ports
+	summaries := make(chan summaryResult, 1)
+	go func() {
+		summary, err := receiveSummary(stream, opts.OnRejected)
+		summaries <- summaryResult{summary: summary, err: err}
+	}()
+
+	err = stream.Send(&pb2.StreamCreatePortsRequest{Request: &pb2.StreamCreatePortsRequest_Options{
+		Options: &pb2.IngestOptions{
+			ErrorPolicy: errorPoliciesPB[opts.ErrorPolicy],
+			Atomic:      opts.Atomic,
+			Replace:     opts.Mode == ReplaceMode,
+			DryRun:      opts.DryRun,
+		},
+	}})
+	switch {
+	case errors.Is(err, io.EOF):
+		// stream is closed by the server, its status is received with the summary
+	case err != nil:
+		return nil, serviceErr("failed to send options to Ports service", err, ErrInvalidPort)
+	default:
+		err = sendPorts(ctx, func(entry *pb2.PortEntry) error {
+			return stream.Send(&pb2.StreamCreatePortsRequest{Request: &pb2.StreamCreatePortsRequest_Entry{Entry: entry}})
+		}, nextPort)
+	}
+
+	var bodyErr *bodyError
+	switch {
+	case errors.As(err, &bodyErr):
+		return abortIngestion(stream, summaries, opts, bodyErr)
+	case err != nil && ctx.Err() != nil && !opts.atomic():
+		summary, recvErr := closeAndRecv(stream, summaries)
+		if recvErr != nil {
+			return nil, recvErr
+		}
+		return summary, ctx.Err()
+	case err != nil:
+		return nil, err
+	}
+	return closeAndRecv(stream, summaries)
+}
+
+type summaryResult struct {
+	summary *pb2.IngestSummary
+	err     error
+}
+
+// receiveSummary receives ports rejected by Ports service, which are passed to onRejected, until the summary.
+func receiveSummary(stream pb2.PortService_StreamCreatePortsClient, onRejected func(PortError),
+) (*pb2.IngestSummary, error) {
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("stream closed without summary: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if summary := resp.GetSummary(); summary != nil {
+			return summary, nil
+		}
+		if onRejected != nil {
+			onRejected(pbToPortError(resp.GetRejected()))
+		}
+	}
+}
+
+// closeAndRecv closes sending side of the stream and waits for the summary from receiveSummary.
+func closeAndRecv(stream pb2.PortService_StreamCreatePortsClient, summaries <-chan summaryResult,
+) (*IngestSummary, error) {
+	if err := stream.CloseSend(); err != nil {
+		return nil, serviceErr("failed to close ports stream to Ports service", err, ErrInvalidPort)
+	}
+	result := <-summaries
+	if result.err != nil {
+		return nil, serviceErr("failed to create ports in Ports service", result.err, ErrInvalidPort)
+	}
+	return pbToIngestSummary(result.summary), nil
+}
+
+// abortIngestion ends the stream when the body can't be decoded any further. Non-atomic stream is closed,
+// so ports stored before the failure are listed in the summary. Atomic stream is canceled instead, which
+// leaves stored ports unchanged. Either way the summary is aborted and bodyErr is its last rejected entry.
+func abortIngestion(stream pb2.PortService_StreamCreatePortsClient, summaries <-chan summaryResult,
+	opts IngestOptions, bodyErr *bodyError,
+) (*IngestSummary, error) {
+	summary := pbToIngestSummary(&pb2.IngestSummary{DryRun: opts.DryRun})
+	if !opts.atomic() {
+		var err error
+		if summary, err = closeAndRecv(stream, summaries); err != nil {
+			return nil, err
+		}
+	}
+	summary.Aborted = true
+	summary.Rejected++
+	summary.Errors = append(summary.Errors, PortError{Reason: bodyErr.Error(), Offset: bodyErr.offset})
+	return summary, nil
+}
+
+// detachedContext keeps values of its parent, but it's never canceled.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
 	return nil
 }
 
-func (s Service) FetchPorts(ctx context.Context) ([]*Port, error) {
-	getPortsResponse, err := s.portsClient.GetPorts(ctx, &emptypb.Empty{})
+// DiffPorts streams all ports returned by nextPort to the Ports service the same way as CreatePorts,
+// but they're only compared to the stored ports.
+func (s Service) DiffPorts(ctx context.Context, nextPort func() (*PortEntry, error)) (*PortsDiff, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := s.portsClient.DiffPorts(ctx)
+	if err != nil {
+		return nil, serviceErr("failed to open ports stream to Ports service", err, ErrInvalidPort)
+	}
+	if err = sendPorts(ctx, stream.Send, nextPort); err != nil {
+		return nil, err
+	}
+
+	diffPb, err := stream.CloseAndRecv()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch ports from Ports service:%w", err)
+		return nil, serviceErr("failed to compare ports in Ports service", err, ErrInvalidPort)
+	}
+	return pbToPortsDiff(diffPb), nil
+}
+
+// sendPorts sends ports returned by nextPort until it returns io.EOF, ctx is canceled or the stream is
+// closed by the server, which status is received after the stream is closed then.
+func sendPorts(ctx context.Context, send func(*pb2.PortEntry) error, nextPort func() (*PortEntry, error)) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entry, err := nextPort()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		req := &pb2.PortEntry{Port: portToPB(entry.Port), Offset: entry.Offset}
+		if entry.DecodeErr != nil {
+			req.Port = &pb2.Port{Id: entry.Port.ID}
+			req.DecodeError = entry.DecodeErr.Error()
+		}
+		err = send(req)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return serviceErr("failed to send port "+entry.Port.ID+" to Ports service", err, ErrInvalidPort)
+		}
 	}
+}
+
+func (s Service) UpdatePort(ctx context.Context, port *Port) error {
+	return s.updatePort(ctx, &pb2.UpdatePortRequest{Port: portToPB(port)})
+}
+
+func (s Service) PatchPort(ctx context.Context, port *Port, fields []string) error {
+	return s.updatePort(ctx, &pb2.UpdatePortRequest{
+		Port:       portToPB(port),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: fields},
+	})
+}
 
-	allPorts := make([]*Port, len(getPortsResponse.Ports))
-	for i, portPb := range getPortsResponse.Ports {
-		allPorts[i] = pbToPort(portPb)
+func (s Service) updatePort(ctx context.Context, req *pb2.UpdatePortRequest) error {
+	if _, err := s.portsClient.UpdatePort(ctx, req); err != nil {
+		return serviceErr("failed to update port in Ports service", err, ErrInvalidPort)
 	}
-	return allPorts, nil
+	return nil
 }
 
-func (sh *ServiceHandler) renderErr(w http.ResponseWriter, errMsg string, status int) {
-	sh.renderResponse(w, errorResp{Error: errMsg}, status)
+func (s Service) DeletePort(ctx context.Context, id string) error {
+	if _, err := s.portsClient.DeletePort(ctx, &pb2.DeletePortRequest{Id: id}); err != nil {
+		return serviceErr("failed to delete port in Ports service", err, ErrInvalidPort)
+	}
+	return nil
 }
 
-func (sh *ServiceHandler) renderResponse(w http.ResponseWriter, res interface{}, status int) {
-	w.Header().Set("Content-Type", "application/json")
+func (s Service) FetchPort(ctx context.Context, id string) (*Port, error) {
+	getPortResponse, err := s.portsClient.GetPort(ctx, &pb2.GetPortRequest{Id: id})
+	if err != nil {
+		return nil, serviceErr("failed to fetch port from Ports service", err, ErrInvalidQuery)
+	}
+	return pbToPort(getPortResponse.Port), nil
+}
 
-	content, err := json.Marshal(res)
+func (s Service) FetchPorts(ctx context.Context, query PortsQuery) (*PortsPage, error) {
+	if !query.paged() {
+		return s.fetchAllPorts(ctx, query)
+	}
+
+	listPortsResponse, err := s.portsClient.ListPorts(ctx, &pb2.ListPortsRequest{
+		PageSize:  query.PageSize,
+		PageToken: query.PageToken,
+		Order:     orderToPB(query),
+		Filter:    filterToPB(query.Filter),
+	})
 	if err != nil {
-		sh.log.Warn("failed to marshal response", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, serviceErr("failed to fetch ports from Ports service", err, ErrInvalidQuery)
+	}
+
+	ports := make([]*Port, len(listPortsResponse.Ports))
+	for i, portPb := range listPortsResponse.Ports {
+		ports[i] = pbToPort(portPb)
+	}
+	return &PortsPage{Ports: ports, NextPageToken: listPortsResponse.NextPageToken}, nil
+}
+
+func (s Service) SearchPorts(ctx context.Context, query string, limit int32) (*SearchResults, error) {
+	searchResponse, err := s.portsClient.SearchPorts(ctx, &pb2.SearchPortsRequest{Query: query, Limit: limit})
+	if err != nil {
+		return nil, serviceErr("failed to search ports in Ports service", err, ErrInvalidQuery)
+	}
+
+	ports := make([]*Port, len(searchResponse.Ports))
+	for i, portPb := range searchResponse.Ports {
+		ports[i] = pbToPort(portPb)
+	}
+	return &SearchResults{Ports: ports}, nil
+}
+
+func (s Service) FindNearestPorts(ctx context.Context, query NearbyQuery) (*NearbyPorts, error) {
+	nearestResponse, err := s.portsClient.FindNearestPorts(ctx, &pb2.FindNearestPortsRequest{
+		Lat:           query.Lat,
+		Lon:           query.Lon,
+		K:             query.K,
+		MaxDistanceKm: query.MaxDistanceKm,
+	})
+	if err != nil {
+		return nil, serviceErr("failed to find nearest ports in Ports service", err, ErrInvalidQuery)
+	}
+
+	nearby := &NearbyPorts{Ports: make([]NearbyPort, len(nearestResponse.Ports))}
+	for i, nearbyPb := range nearestResponse.Ports {
+		nearby.Ports[i] = NearbyPort{Port: pbToPort(nearbyPb.Port), DistanceKm: nearbyPb.DistanceKm}
+	}
+	return nearby, nil
+}
+
+func (s Service) FindPortsInBoundingBox(ctx context.Context, box BoundingBox, limit int32) (*PortsInBox, error) {
+	boxResponse, err := s.portsClient.FindPortsInBoundingBox(ctx, &pb2.FindPortsInBoundingBoxRequest{
+		MinLat: box.MinLat,
+		MinLon: box.MinLon,
+		MaxLat: box.MaxLat,
+		MaxLon: box.MaxLon,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, serviceErr("failed to find ports in bounding box in Ports service", err, ErrInvalidQuery)
 	}
 
-	w.WriteHeader(status)
+	ports := make([]*Port, len(boxResponse.Ports))
+	for i, portPb := range boxResponse.Ports {
+		ports[i] = pbToPort(portPb)
+	}
+	return &PortsInBox{Ports: ports}, nil
+}
 
-	if _, err = w.Write(content); err != nil {
-		sh.log.Warn("failed to send response", zap.Error(err))
+// fetchAllPorts uses server streaming, so the number of ports isn't limited by
+// the maximum size of a single gRPC message.
+func (s Service) fetchAllPorts(ctx context.Context, query PortsQuery) (*PortsPage, error) {
+	nextPort, err := s.StreamPorts(ctx, query)
+	if err != nil {
+		return nil, err
 	}
+
+	allPorts := make([]*Port, 0)
+	for {
+		port, err := nextPort()
+		if errors.Is(err, io.EOF) {
+			return &PortsPage{Ports: allPorts}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		allPorts = append(allPorts, port)
+	}
+}
+
+func (s Service) StreamPorts(ctx context.Context, query PortsQuery) (func() (*Port, error), error) {
+	stream, err := s.portsClient.StreamPorts(ctx, &pb2.StreamPortsRequest{
+		Order:  orderToPB(query),
+		Filter: filterToPB(query.Filter),
+	})
+	if err != nil {
+		return nil, serviceErr("failed to open ports stream from Ports service", err, ErrInvalidQuery)
+	}
+	return func() (*Port, error) {
+		portPb, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, serviceErr("failed to fetch ports from Ports service", err, ErrInvalidQuery)
+		}
+		return pbToPort(portPb), nil
+	}, nil
 }
@@ -6,10 +6,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,9 +46,12 @@ func TestPortsStoring(t *testing.T) {
 
 		// then
 		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var summary IngestSummary
+		err := json.NewDecoder(recorder.Body).Decode(&summary)
+		require.NoError(t, err)
 		// all ports from ports.json file
-		expectedResponse := `["AEAJM","AEAUH"]`
-		assert.Equal(t, expectedResponse, recorder.Body.String())
+		assert.Equal(t, uint32(2), summary.Created+summary.Updated)
+		assert.Zero(t, summary.Rejected)
 
 		// assert that json has stored all values by requesting next call
 		recorder = httptest.NewRecorder()
@@ -55,7 +60,7 @@ func TestPortsStoring(t *testing.T) {
 		handler.ports(recorder, req)
 
 		var ports []*Port
-		err := json.NewDecoder(recorder.Body).Decode(&ports)
+		err = json.NewDecoder(recorder.Body).Decode(&ports)
 		require.NoError(t, err)
 
 		// TODO: this can be validated in much better way, to compare all the fields
@@ -72,6 +77,624 @@ func TestPortsStoring(t *testing.T) {
 		assert.Equal(t, http.StatusOK, recorder.Code)
 		assert.Equal(t, len(expectedPortIDs), counter)
 	})
+
+	t.Run("should report ports deleted by dry run of replacement without deleting them", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		handler.ports(httptest.NewRecorder(), req)
+
+		// when
+		req = httptest.NewRequest(http.MethodPost, "/"+portsEndpointName+"?mode=replace&dry_run=true",
+			bytes.NewBufferString(`{"AEAJM": {"name": "Ajman", "code": "52000"}}`))
+		req.Header.Set("Content-Type", jsonContentType)
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var summary IngestSummary
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&summary))
+		assert.True(t, summary.DryRun)
+		assert.Equal(t, []string{"AEAJM"}, summary.UpdatedIDs)
+		assert.Contains(t, summary.DeletedIDs, "AEAUH")
+
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/"+portsEndpointName+"/AEAUH", nil)
+		handler.port(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("should report differences of uploaded ports to the stored ones", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		handler.ports(httptest.NewRecorder(), req)
+
+		// when
+		req = httptest.NewRequest(http.MethodPost, "/"+portsEndpointName+"/diff", bytes.NewBufferString(`{"AEAJM": {
+			"name": "Ajman Port", "city": "Ajman", "country": "United Arab Emirates",
+			"coordinates": [55.5136433, 25.4052165], "timezone": "Asia/Dubai", "unlocs": ["AEAJM", "AEAJX"],
+			"code": "52000"}}`))
+		req.Header.Set("Content-Type", jsonContentType)
+		recorder := httptest.NewRecorder()
+		handler.portsDiff(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var diff PortsDiff
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&diff))
+		assert.Equal(t, uint32(1), diff.Changed)
+		require.Len(t, diff.Ports, 1)
+		assert.Equal(t, []FieldDiff{
+			{Field: "name", Change: FieldChanged, Old: "Ajman", New: "Ajman Port"},
+			{Field: "province", Change: FieldRemoved, Old: "Ajman"},
+			{Field: "unlocs", Change: FieldChanged, Old: []any{"AEAJM"}, New: []any{"AEAJM", "AEAJX"}},
+		}, diff.Ports[0].Fields)
+
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/"+portsEndpointName+"/AEAJM", nil)
+		handler.port(recorder, req)
+		var port Port
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&port))
+		assert.Equal(t, "Ajman", port.Name)
+	})
+
+	t.Run("should export ports in the format of uploaded file", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		handler.ports(httptest.NewRecorder(), req)
+		uploaded, err := os.ReadFile(testFilePath)
+		require.NoError(t, err)
+		var expected map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(uploaded, &expected))
+
+		// when
+		recorder := httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/"+portsEndpointName+"/export?country=United+Arab+Emirates", nil)
+		handler.exportPorts(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var exported map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &exported))
+		for id, expectedPort := range expected {
+			assert.JSONEq(t, string(expectedPort), string(exported[id]))
+		}
+	})
+
+	t.Run("should list ports uploaded as csv", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		const csvPorts = "id,name,city,country,alias,regions,latitude,longitude,province,timezone,unlocs,code\n" +
+			"PLGDN,Gdansk,Gdansk,Poland,Danzig,,54.35,18.65,Pomeranian,Europe/Warsaw,PLGDN,45100\n" +
+			"PLGDY,Gdynia,Gdynia,Poland,,,54.5,18.55,Pomeranian,Europe/Warsaw,PLGDY|PLGDX,45101\n"
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, strings.NewReader(csvPorts))
+		req.Header.Set("Content-Type", csvContentType)
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		// other tests expect only ports of the test file to be stored
+		defer func() {
+			for _, id := range []string{"PLGDN", "PLGDY"} {
+				handler.port(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/"+portsEndpointName+"/"+id, nil))
+			}
+		}()
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/"+portsEndpointName+"?province=Pomeranian", nil)
+		req.Header.Set("Accept", csvContentType)
+		handler.ports(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, csvContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, csvPorts, recorder.Body.String())
+	})
+
+	t.Run("should report ports stored before body is truncated", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		const csvPorts = "id,name,city,country,unlocs,code\n" +
+			"PLGDN,Gdansk,Gdansk,Poland,PLGDN,45100\n" +
+			"PLGDY,\"Gdynia,Gdynia,Poland,PLGDY,45101\n"
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, strings.NewReader(csvPorts))
+		req.Header.Set("Content-Type", csvContentType)
+		recorder := httptest.NewRecorder()
+		// other tests expect only ports of the test file to be stored
+		defer handler.port(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/"+portsEndpointName+"/PLGDN", nil))
+
+		// when
+		handler.ports(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		var summary IngestSummary
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&summary))
+		assert.True(t, summary.Aborted)
+		assert.Equal(t, []string{"PLGDN"}, summary.CreatedIDs)
+		require.Len(t, summary.Errors, 1)
+		assert.Equal(t, int64(72), summary.Errors[0].Offset)
+	})
+
+	t.Run("should store nothing of atomic ingestion when body is truncated", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		const csvPorts = "id,name,city,country,unlocs,code\n" +
+			"PLGDN,Gdansk,Gdansk,Poland,PLGDN,45100\n" +
+			"PLGDY,\"Gdynia,Gdynia,Poland,PLGDY,45101\n"
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName+"?atomic=true", strings.NewReader(csvPorts))
+		req.Header.Set("Content-Type", csvContentType)
+		recorder := httptest.NewRecorder()
+
+		// when
+		handler.ports(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		var summary IngestSummary
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&summary))
+		assert.True(t, summary.Aborted)
+		assert.Empty(t, summary.CreatedIDs)
+		recorder = httptest.NewRecorder()
+		handler.port(recorder, httptest.NewRequest(http.MethodGet, "/"+portsEndpointName+"/PLGDN", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should report rejected ports and ports stored before ingestion is canceled", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		// other tests expect only ports of the test file to be stored
+		defer handler.port(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/"+portsEndpointName+"/PLGDN", nil))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		entries := []*PortEntry{
+			{Port: &Port{ID: "PLGDN", Name: "Gdansk", City: "Gdansk", Country: "Poland", Code: "45100"}},
+			{Port: &Port{ID: "PLGDY"}, Offset: 64, DecodeErr: errors.New("invalid name")},
+		}
+		rejected := make(chan PortError, 1)
+		opts := IngestOptions{ErrorPolicy: SkipInvalid, OnRejected: func(portErr PortError) {
+			rejected <- portErr
+		}}
+
+		// when
+		summary, err := handler.svc.CreatePorts(ctx, opts, func() (*PortEntry, error) {
+			if len(entries) == 0 {
+				// the ingestion is canceled once the rejected port is reported
+				<-rejected
+				cancel()
+				return nil, ctx.Err()
+			}
+			entry := entries[0]
+			entries = entries[1:]
+			return entry, nil
+		})
+
+		// then
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotNil(t, summary)
+		assert.Equal(t, []string{"PLGDN"}, summary.CreatedIDs)
+		require.Len(t, summary.Errors, 1)
+		assert.Equal(t, "PLGDY", summary.Errors[0].PortID)
+	})
+
+	t.Run("should fetch stored ports page by page", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/ports?page_size=1", nil)
+		handler.ports(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var page PortsPage
+		err := json.NewDecoder(recorder.Body).Decode(&page)
+		require.NoError(t, err)
+		require.Len(t, page.Ports, 1)
+		assert.Equal(t, "AEAJM", page.Ports[0].ID)
+		require.NotEmpty(t, page.NextPageToken)
+
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/ports?page_size=1&page_token="+page.NextPageToken, nil)
+		handler.ports(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		err = json.NewDecoder(recorder.Body).Decode(&page)
+		require.NoError(t, err)
+		require.Len(t, page.Ports, 1)
+		assert.Equal(t, "AEAUH", page.Ports[0].ID)
+	})
+
+	t.Run("should fetch single port by id", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/ports/AEAJM", nil)
+		handler.port(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var port Port
+		err := json.NewDecoder(recorder.Body).Decode(&port)
+		require.NoError(t, err)
+		assert.Equal(t, "AEAJM", port.ID)
+		assert.Equal(t, "Ajman", port.Name)
+	})
+
+	t.Run("should return not found for unknown port", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ports/UNKNOWN", nil)
+
+		// when
+		handler.port(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should update and delete existing port", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPut, "/ports/AEAJM",
+			bytes.NewBufferString(`{"name":"Ajman updated","code":"52000","unlocs":["AEAJM"]}`))
+		handler.port(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/ports/AEAJM", nil)
+		handler.port(recorder, req)
+		var port Port
+		err := json.NewDecoder(recorder.Body).Decode(&port)
+		require.NoError(t, err)
+		assert.Equal(t, "Ajman updated", port.Name)
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, "/ports/AEAJM", nil)
+		handler.port(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/ports/AEAJM", nil)
+		handler.port(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should patch only fields sent in merge patch", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPatch, "/ports/AEAUH",
+			bytes.NewBufferString(`{"alias":["Abu Zaby"],"province":null}`))
+		req.Header.Set("Content-Type", mergePatchContentType)
+		handler.port(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var port Port
+		err := json.NewDecoder(recorder.Body).Decode(&port)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Abu Zaby"}, port.Alias)
+		assert.Empty(t, port.Province)
+		assert.Equal(t, "Abu Dhabi", port.Name)
+		assert.Equal(t, "Asia/Dubai", port.Timezone)
+	})
+
+	t.Run("should return not found when updating or deleting unknown port", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		// when
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/ports/XXUNK", bytes.NewBufferString(`{"code":"52000"}`))
+		handler.port(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, "/ports/UNKNOWN", nil)
+		handler.port(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should fetch ports in requested order", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		for _, test := range []struct {
+			url         string
+			expectedIDs []string
+		}{
+			{url: "/ports?order_by=name", expectedIDs: []string{"AEAUH", "AEAJM"}},
+			{url: "/ports?order_by=name&order=desc", expectedIDs: []string{"AEAJM", "AEAUH"}},
+			{url: "/ports?order_by=id&order=desc&page_size=10", expectedIDs: []string{"AEAUH", "AEAJM"}},
+		} {
+			// when
+			recorder = httptest.NewRecorder()
+			handler.ports(recorder, httptest.NewRequest(http.MethodGet, test.url, nil))
+
+			// then
+			require.Equal(t, http.StatusOK, recorder.Code, test.url)
+			ports := listedPorts(t, recorder)
+			require.Len(t, ports, 2)
+			assert.Equal(t, test.expectedIDs, []string{ports[0].ID, ports[1].ID}, test.url)
+		}
+	})
+
+	t.Run("should fetch only ports matching filter", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		for _, url := range []string{
+			"/ports?country=united+arab+emirates&code=52001",
+			"/ports?unloc=AEAUH&timezone=Asia/Dubai&page_size=10",
+		} {
+			// when
+			recorder = httptest.NewRecorder()
+			handler.ports(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+
+			// then
+			require.Equal(t, http.StatusOK, recorder.Code, url)
+			ports := listedPorts(t, recorder)
+			require.Len(t, ports, 1, url)
+			assert.Equal(t, "AEAUH", ports[0].ID)
+		}
+	})
+
+	t.Run("should search ports by name", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		handler.searchPorts(recorder, httptest.NewRequest(http.MethodGet, "/ports/search?q=abu+zaby", nil))
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var results SearchResults
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&results))
+		require.Len(t, results.Ports, 1)
+		assert.Equal(t, "AEAUH", results.Ports[0].ID)
+	})
+
+	t.Run("should find ports by location", func(t *testing.T) {
+		// given
+		requestBody, writer := createRequestBodyFromTestFile(t, testFilePath)
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/"+portsEndpointName, requestBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		handler.ports(recorder, req)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		// when
+		recorder = httptest.NewRecorder()
+		handler.nearbyPorts(recorder, httptest.NewRequest(http.MethodGet, "/ports/nearby?lat=24.5&lon=54.4&k=1", nil))
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var nearby NearbyPorts
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&nearby))
+		require.Len(t, nearby.Ports, 1)
+		assert.Equal(t, "AEAUH", nearby.Ports[0].Port.ID)
+		assert.Less(t, nearby.Ports[0].DistanceKm, 10.0)
+
+		// when
+		recorder = httptest.NewRecorder()
+		handler.portsWithin(recorder, httptest.NewRequest(http.MethodGet,
+			"/ports/within?min_lat=25&min_lon=55&max_lat=26&max_lon=56", nil))
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var within PortsInBox
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&within))
+		require.Len(t, within.Ports, 1)
+		assert.Equal(t, "AEAJM", within.Ports[0].ID)
+	})
+
+	t.Run("should reject invalid location", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		for url, handle := range map[string]http.HandlerFunc{
+			"/ports/nearby?lon=54.4":                                             handler.nearbyPorts,
+			"/ports/nearby?lat=95&lon=54.4":                                      handler.nearbyPorts,
+			"/ports/within?min_lat=25&min_lon=55&max_lat=26":                     handler.portsWithin,
+			"/ports/within?min_lat=25&min_lon=55&max_lat=20&max_lon=56":          handler.portsWithin,
+			"/ports/within?min_lat=25&min_lon=55&max_lat=26&max_lon=56&limit=-1": handler.portsWithin,
+		} {
+			recorder := httptest.NewRecorder()
+
+			// when
+			handle(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, url)
+		}
+	})
+
+	t.Run("should reject search without query", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		recorder := httptest.NewRecorder()
+
+		// when
+		handler.searchPorts(recorder, httptest.NewRequest(http.MethodGet, "/ports/search?q=", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should reject unknown order", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+
+		for _, url := range []string{"/ports?order_by=city", "/ports?order=random"} {
+			recorder := httptest.NewRecorder()
+
+			// when
+			handler.ports(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, url)
+		}
+	})
+
+	t.Run("should reject invalid port listing its invalid fields", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/ports/AEAJM",
+			bytes.NewBufferString(`{"code":"52000","timezone":"Mars/Olympus","unlocs":["AEAJM","ajman"]}`))
+
+		// when
+		handler.port(recorder, req)
+
+		// then
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		var resp errorResp
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+		assert.Equal(t, "invalid_argument", resp.Code)
+		require.Len(t, resp.FieldViolations, 2)
+		assert.Equal(t, "timezone", resp.FieldViolations[0].Field)
+		assert.Equal(t, "unlocs[1]", resp.FieldViolations[1].Field)
+	})
+
+	t.Run("should reject invalid page token", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ports?page_size=1&page_token=abc", nil)
+
+		// when
+		handler.ports(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should reject invalid page size", func(t *testing.T) {
+		// given
+		handler, conn := setupServer(t)
+		defer conn.Close()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ports?page_size=abc", nil)
+
+		// when
+		handler.ports(recorder, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+// listedPorts decodes ports listed by GET /ports, which are a json array unless the request is paged.
+func listedPorts(t *testing.T, recorder *httptest.ResponseRecorder) []*Port {
+	if bytes.HasPrefix(bytes.TrimSpace(recorder.Body.Bytes()), []byte("[")) {
+		var ports []*Port
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&ports))
+		return ports
+	}
+	var page PortsPage
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&page))
+	return page.Ports
 }
 
 func createRequestBodyFromTestFile(t *testing.T, testFilePath string) (*bytes.Buffer, *multipart.Writer) {
@@ -98,7 +721,7 @@ func setupServer(t *testing.T) (sh *ServiceHandler, conn *grpc.ClientConn) {
 	require.NoError(t, err)
 	service := NewService(log, portsgrpc.NewPortServiceClient(conn))
 	mux := http.NewServeMux()
-	handler := NewServiceHandler(service, &http.Server{Handler: mux}, log)
+	handler := NewServiceHandler(service, &http.Server{Handler: mux}, 0, 0, log)
 	handler.Register(mux)
 	return handler, conn
 }
@@ -0,0 +1,1176 @@
+package webapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type serviceHandlerSuite struct {
+	suite.Suite
+
+	svc *fakePortsService
+	mux *http.ServeMux
+}
+
+func TestServiceHandler(t *testing.T) {
+	suite.Run(t, &serviceHandlerSuite{})
+}
+
+func (s *serviceHandlerSuite) SetupTest() {
+	s.svc = &fakePortsService{ports: map[string]*Port{}}
+	s.mux = http.NewServeMux()
+	NewServiceHandler(s.svc, nil, 0, 0, zap.NewNop()).Register(s.mux)
+}
+
+func (s *serviceHandlerSuite) TestListingPorts() {
+	s.Run("should return ports", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports?page_size=1&order=desc", nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().Equal(jsonContentType, recorder.Header().Get("Content-Type"))
+		var page PortsPage
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&page))
+		s.Assert().Equal([]*Port{{ID: "AEAJM", Name: "Ajman"}}, page.Ports)
+		s.Assert().Equal(PortsQuery{PageSize: 1, OrderBy: OrderByID, Descending: true}, s.svc.query)
+	})
+
+	s.Run("should return all ports as json array when request isn't paged", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports?order_by=name", nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		var ports []*Port
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&ports))
+		s.Assert().Equal([]*Port{{ID: "AEAJM", Name: "Ajman"}}, ports)
+	})
+
+	s.Run("should return empty json array when there are no ports", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports", nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().JSONEq(`[]`, recorder.Body.String())
+	})
+
+	s.Run("should write only error when query is invalid", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports?page_size=-1", nil))
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+	})
+
+	s.Run("should write only error when service fails", func() {
+		// given
+		s.SetupTest()
+		s.svc.err = &ServiceError{kind: ErrInvalidQuery, Message: "invalid page token"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports?page_token=abc", nil))
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+	})
+}
+
+func (s *serviceHandlerSuite) TestIngestingPorts() {
+	s.Run("should create ports from uploaded file", func() {
+		// given
+		s.SetupTest()
+		body, contentType := s.multipartBody(`{"AEAJM": {"name": "Ajman"}, "AEAUH": {"name": "Abu Dhabi"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/ports", body)
+		req.Header.Set("Content-Type", contentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().Equal(uint32(2), summary.Created)
+		s.Assert().Len(s.svc.ports, 2)
+	})
+
+	s.Run("should create ports from json body", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports",
+			bytes.NewBufferString(`{"AEAJM": {"name": "Ajman"}, "AEAUH": {"name": "Abu Dhabi"}}`))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		s.Assert().Equal(&Port{ID: "AEAUH", Name: "Abu Dhabi"}, s.svc.ports["AEAUH"])
+		s.Assert().Len(s.svc.ports, 2)
+	})
+
+	s.Run("should create ports from newline delimited json body", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports",
+			bytes.NewBufferString("{\"id\": \"AEAJM\", \"name\": \"Ajman\"}\n\n{\"id\": \"AEAUH\", \"name\": \"Abu Dhabi\"}\n"))
+		req.Header.Set("Content-Type", ndjsonContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		s.Assert().Equal(&Port{ID: "AEAJM", Name: "Ajman"}, s.svc.ports["AEAJM"])
+		s.Assert().Len(s.svc.ports, 2)
+	})
+
+	s.Run("should abort ingestion at malformed newline delimited json", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports",
+			bytes.NewBufferString("{\"id\": \"AEAJM\"}\n{\"id\": \"AEAUH\", \"name\": }\n"))
+		req.Header.Set("Content-Type", ndjsonContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusUnprocessableEntity, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().True(summary.Aborted)
+		s.Assert().Equal([]string{"AEAJM"}, summary.CreatedIDs)
+		s.Require().Len(summary.Errors, 1)
+		s.Assert().Equal(int64(16), summary.Errors[0].Offset)
+		s.Assert().Contains(summary.Errors[0].Reason, "failed to decode port at offset 16")
+	})
+
+	s.Run("should reject body of unsupported media type", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "text/plain")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusUnsupportedMediaType, recorder.Code)
+		s.assertErrorBody(recorder, "unsupported_media_type")
+		s.Assert().Zero(s.svc.calls)
+	})
+
+	s.Run("should reject multipart form without ports part", func() {
+		// given
+		s.SetupTest()
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		s.Require().NoError(writer.WriteField("other", "value"))
+		s.Require().NoError(writer.Close())
+		req := httptest.NewRequest(http.MethodPost, "/ports", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+	})
+
+	s.Run("should abort ingestion at malformed json", func() {
+		// given
+		s.SetupTest()
+		body, contentType := s.multipartBody(`{"AEAJM": {"name": "Ajman"}, "AEAUH": {"name" "Abu Dhabi"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/ports", body)
+		req.Header.Set("Content-Type", contentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusUnprocessableEntity, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().True(summary.Aborted)
+		s.Assert().Equal(uint32(1), summary.Rejected)
+		s.Require().Len(summary.Errors, 1)
+		s.Assert().Equal(int64(29), summary.Errors[0].Offset)
+	})
+}
+
+func (s *serviceHandlerSuite) TestIngestingTruncatedBody() {
+	const body = `{"AEAJM": {"name": "Ajman"}, "AEAUH": {"name": "Abu Dhabi"}, "AEDXB": {"na`
+	tests := map[string]struct {
+		url         string
+		expectedIDs []string
+	}{
+		"should report ports stored before the body is truncated": {
+			url:         "/ports",
+			expectedIDs: []string{"AEAJM", "AEAUH"},
+		},
+		"should store nothing of atomic ingestion": {
+			url: "/ports?atomic=true",
+		},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+			req := httptest.NewRequest(http.MethodPost, test.url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", jsonContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(http.StatusUnprocessableEntity, recorder.Code)
+			var summary IngestSummary
+			s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+			s.Assert().True(summary.Aborted)
+			s.Assert().ElementsMatch(test.expectedIDs, summary.CreatedIDs)
+			s.Assert().Len(s.svc.ports, len(test.expectedIDs))
+			s.Require().Len(summary.Errors, 1)
+			s.Assert().Equal(int64(61), summary.Errors[0].Offset)
+			s.Assert().Contains(summary.Errors[0].Reason, "unexpected EOF")
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestIngestingWithErrorPolicy() {
+	// second port can't be decoded, as its name isn't a string
+	const body = `{"AEAJM": {"name": "Ajman"}, "AEAUH": {"name": 1}, "AEDXB": {"name": "Dubai"}}`
+	tests := map[string]struct {
+		url            string
+		expectedCode   int
+		expectedPolicy ErrorPolicy
+		expectedIDs    []string
+	}{
+		"skip invalid by default": {
+			url:            "/ports",
+			expectedCode:   http.StatusCreated,
+			expectedPolicy: SkipInvalid,
+			expectedIDs:    []string{"AEAJM", "AEDXB"},
+		},
+		"fail fast": {
+			url:            "/ports?error_policy=fail_fast",
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedPolicy: FailFast,
+			expectedIDs:    []string{"AEAJM"},
+		},
+		"all or nothing": {
+			url:            "/ports?error_policy=all_or_nothing",
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedPolicy: AllOrNothing,
+		},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+			req := httptest.NewRequest(http.MethodPost, test.url, bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", jsonContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(test.expectedCode, recorder.Code)
+			s.Assert().Equal(test.expectedPolicy, s.svc.ingestOpts.ErrorPolicy)
+			var summary IngestSummary
+			s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+			s.Assert().Equal(test.expectedIDs, summary.CreatedIDs)
+			s.Require().Len(summary.Errors, 1)
+			s.Assert().Equal("AEAUH", summary.Errors[0].PortID)
+			s.Assert().Contains(summary.Errors[0].Reason, "failed to decode port")
+			// offset of "AEAUH" key
+			s.Assert().Equal(int64(29), summary.Errors[0].Offset)
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestIngestingAtomically() {
+	tests := map[string]struct {
+		url            string
+		expectedCode   int
+		expectedAtomic bool
+	}{
+		"not atomic by default": {
+			url:          "/ports",
+			expectedCode: http.StatusCreated,
+		},
+		"atomic": {
+			url:            "/ports?atomic=true",
+			expectedCode:   http.StatusCreated,
+			expectedAtomic: true,
+		},
+		"invalid atomic": {
+			url:          "/ports?atomic=always",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+			req := httptest.NewRequest(http.MethodPost, test.url, bytes.NewBufferString(`{"AEAJM": {"name": "Ajman"}}`))
+			req.Header.Set("Content-Type", jsonContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(test.expectedCode, recorder.Code)
+			s.Assert().Equal(test.expectedAtomic, s.svc.ingestOpts.Atomic)
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestIngestingInReplaceMode() {
+	tests := map[string]struct {
+		url          string
+		expectedCode int
+		expectedOpts IngestOptions
+		// expectedStored is the number of stored ports
+		expectedStored int
+	}{
+		"upsert by default": {
+			url:            "/ports",
+			expectedCode:   http.StatusCreated,
+			expectedOpts:   IngestOptions{ErrorPolicy: SkipInvalid, Mode: UpsertMode},
+			expectedStored: 1,
+		},
+		"replace": {
+			url:            "/ports?mode=replace",
+			expectedCode:   http.StatusCreated,
+			expectedOpts:   IngestOptions{ErrorPolicy: SkipInvalid, Mode: ReplaceMode},
+			expectedStored: 1,
+		},
+		"dry run of replace": {
+			url:          "/ports?mode=replace&dry_run=true",
+			expectedCode: http.StatusOK,
+			expectedOpts: IngestOptions{ErrorPolicy: SkipInvalid, Mode: ReplaceMode, DryRun: true},
+		},
+		"unknown mode": {
+			url:          "/ports?mode=merge",
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid dry run": {
+			url:          "/ports?dry_run=maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+			req := httptest.NewRequest(http.MethodPost, test.url, bytes.NewBufferString(`{"AEAJM": {"name": "Ajman"}}`))
+			req.Header.Set("Content-Type", jsonContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(test.expectedCode, recorder.Code)
+			s.Assert().Equal(test.expectedOpts, s.svc.ingestOpts)
+			s.Assert().Len(s.svc.ports, test.expectedStored)
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestDiffingPorts() {
+	s.Run("should compare ports without storing them", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman"}
+		s.svc.ports["AEAUH"] = &Port{ID: "AEAUH", Name: "Abu Dhabi"}
+		req := httptest.NewRequest(http.MethodPost, "/ports/diff", bytes.NewBufferString(
+			"{\"id\": \"AEAJM\", \"name\": \"Ajman\"}\n"+
+				"{\"id\": \"AEAUH\", \"name\": \"Abu Dhabi Port\"}\n"+
+				"{\"id\": \"AEDXB\", \"name\": \"Dubai\"}\n"))
+		req.Header.Set("Content-Type", ndjsonContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		var diff PortsDiff
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&diff))
+		s.Assert().Equal(uint32(1), diff.New)
+		s.Assert().Equal(uint32(1), diff.Changed)
+		s.Assert().Equal(uint32(1), diff.Unchanged)
+		s.Require().Len(diff.Ports, 2)
+		s.Assert().Equal(PortDiff{PortID: "AEAUH", Status: PortChanged, Offset: 33}, diff.Ports[0])
+		s.Assert().Equal(PortDiff{PortID: "AEDXB", Status: PortNew, Offset: 75}, diff.Ports[1])
+		s.Assert().Equal("Abu Dhabi", s.svc.ports["AEAUH"].Name)
+		s.Assert().Len(s.svc.ports, 2)
+	})
+
+	s.Run("should only accept POST", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/diff", nil))
+
+		// then
+		s.Assert().Equal(http.StatusMethodNotAllowed, recorder.Code)
+		s.Assert().Equal(http.MethodPost, recorder.Header().Get("Allow"))
+	})
+
+	s.Run("should reject body of unsupported media type", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports/diff", bytes.NewBufferString("AEAJM,Ajman"))
+		req.Header.Set("Content-Type", "text/plain")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusUnsupportedMediaType, recorder.Code)
+		s.Assert().Zero(s.svc.calls)
+	})
+}
+
+func (s *serviceHandlerSuite) TestReportingOffsetsOfNewlineDelimitedPorts() {
+	// given
+	s.SetupTest()
+	req := httptest.NewRequest(http.MethodPost, "/ports",
+		bytes.NewBufferString("{\"id\": \"AEAJM\"}\n\n  {\"id\": \"AEAUH\", \"alias\": \"Abu Dhabi\"}\n"))
+	req.Header.Set("Content-Type", ndjsonContentType)
+
+	// when
+	recorder := s.serve(req)
+
+	// then
+	s.Require().Equal(http.StatusCreated, recorder.Code)
+	var summary IngestSummary
+	s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+	s.Assert().Equal([]string{"AEAJM"}, summary.CreatedIDs)
+	s.Assert().Equal([]PortError{{
+		PortID: "AEAUH",
+		Reason: "failed to decode port: json: cannot unmarshal string into Go struct field Port.alias of type []string",
+		Offset: 19,
+	}}, summary.Errors)
+}
+
+func (s *serviceHandlerSuite) TestUploadingLongerThanServerTimeouts() {
+	tests := map[string]struct {
+		path         string
+		expectedCode int
+	}{
+		"should ingest ports": {path: "/ports", expectedCode: http.StatusCreated},
+		"should diff ports":   {path: "/ports/diff", expectedCode: http.StatusOK},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+
+			// when
+			resp := postSlowly(s.T(), s.mux, test.path, jsonContentType, `{"AEAJM": {"name": "Ajman"},`, ` "AEAUH": {}}`)
+
+			// then
+			s.Assert().Equal(test.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestRejectingUnknownErrorPolicy() {
+	// given
+	s.SetupTest()
+	req := httptest.NewRequest(http.MethodPost, "/ports?error_policy=ignore", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", jsonContentType)
+
+	// when
+	recorder := s.serve(req)
+
+	// then
+	s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+	s.assertErrorBody(recorder, "invalid_argument")
+	s.Assert().Zero(s.svc.calls)
+}
+
+func (s *serviceHandlerSuite) TestRoutingByMethod() {
+	tests := map[string]struct {
+		method      string
+		url         string
+		allowHeader string
+	}{
+		"ports": {
+			method: http.MethodPut, url: "/ports", allowHeader: "GET, POST",
+		},
+		"single port": {
+			method: http.MethodPost, url: "/ports/AEAJM", allowHeader: "DELETE, GET, PATCH, PUT",
+		},
+		"search": {
+			method: http.MethodDelete, url: "/ports/search?q=ajman", allowHeader: "GET",
+		},
+		"nearby": {
+			method: http.MethodPost, url: "/ports/nearby?lat=1&lon=1", allowHeader: "GET",
+		},
+		"within": {
+			method: http.MethodPatch, url: "/ports/within", allowHeader: "GET",
+		},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+
+			// when
+			recorder := s.serve(httptest.NewRequest(test.method, test.url, nil))
+
+			// then
+			s.Assert().Equal(http.StatusMethodNotAllowed, recorder.Code)
+			s.Assert().Equal(test.allowHeader, recorder.Header().Get("Allow"))
+			s.assertErrorBody(recorder, "method_not_allowed")
+			s.Assert().Zero(s.svc.calls)
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestNegotiatingContent() {
+	tests := map[string]struct {
+		accept       string
+		expectedCode int
+	}{
+		"missing accept header": {accept: "", expectedCode: http.StatusOK},
+		"json":                  {accept: "application/json", expectedCode: http.StatusOK},
+		"any type":              {accept: "text/html, */*;q=0.1", expectedCode: http.StatusOK},
+		"any application type":  {accept: "application/*", expectedCode: http.StatusOK},
+		"only html":             {accept: "text/html", expectedCode: http.StatusNotAcceptable},
+		"json refused":          {accept: "*/*, application/json;q=0", expectedCode: http.StatusNotAcceptable},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+			s.svc.ports["AEAJM"] = &Port{ID: "AEAJM"}
+			req := httptest.NewRequest(http.MethodGet, "/ports/AEAJM", nil)
+			req.Header.Set("Accept", test.accept)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Assert().Equal(test.expectedCode, recorder.Code)
+			s.Assert().Equal(jsonContentType, recorder.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestHandlingSinglePort() {
+	s.Run("should return port", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/AEAJM", nil))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		var port Port
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&port))
+		s.Assert().Equal(Port{ID: "AEAJM", Name: "Ajman"}, port)
+	})
+
+	s.Run("should return not found for nested path", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/AEAJM/alias", nil))
+
+		// then
+		s.Assert().Equal(http.StatusNotFound, recorder.Code)
+		s.assertErrorBody(recorder, "not_found")
+		s.Assert().Zero(s.svc.calls)
+	})
+
+	s.Run("should update port", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodPut, "/ports/AEAJM", bytes.NewBufferString(`{"name":"Ajman port"}`)))
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		s.Assert().Equal(&Port{ID: "AEAJM", Name: "Ajman port"}, s.svc.ports["AEAJM"])
+	})
+
+	s.Run("should reject port with id other than in the path", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodPut, "/ports/AEAJM", bytes.NewBufferString(`{"id":"AEAUH"}`)))
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+		s.Assert().Zero(s.svc.calls)
+	})
+
+	s.Run("should render field violations of invalid port", func() {
+		// given
+		s.SetupTest()
+		s.svc.err = &ServiceError{
+			kind:            ErrInvalidPort,
+			Message:         "invalid port",
+			FieldViolations: []FieldViolation{{Field: "timezone", Description: "must be IANA time zone name"}},
+		}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodPut, "/ports/AEAJM", bytes.NewBufferString(`{}`)))
+
+		// then
+		s.Require().Equal(http.StatusBadRequest, recorder.Code)
+		resp := s.assertErrorBody(recorder, "invalid_argument")
+		s.Assert().Equal(s.svc.err.(*ServiceError).FieldViolations, resp.FieldViolations)
+	})
+
+	s.Run("should patch port", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman", City: "Ajman"}
+		req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(`{"city":null,"name":"Ajman port"}`))
+		req.Header.Set("Content-Type", mergePatchContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusOK, recorder.Code)
+		var port Port
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&port))
+		s.Assert().Equal(Port{ID: "AEAJM", Name: "Ajman port"}, port)
+		s.Assert().Equal([]string{"city", "name"}, s.svc.patchedFields)
+	})
+
+	for name, body := range map[string]string{"empty": `{}`, "only id": `{"id":"AEAJM"}`} {
+		s.Run("should leave port unchanged by patch with "+name, func() {
+			// given
+			s.SetupTest()
+			s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Name: "Ajman"}
+			req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", mergePatchContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(http.StatusOK, recorder.Code)
+			var port Port
+			s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&port))
+			s.Assert().Equal(Port{ID: "AEAJM", Name: "Ajman"}, port)
+			s.Assert().Nil(s.svc.patchedFields)
+		})
+	}
+
+	s.Run("should return not found for empty patch of missing port", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", mergePatchContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusNotFound, recorder.Code)
+		s.assertErrorBody(recorder, "not_found")
+	})
+
+	locationPatches := map[string]struct {
+		body             string
+		expectedLocation *GeoPoint
+	}{
+		"latitude":           {body: `{"location":{"lat":5}}`, expectedLocation: &GeoPoint{Lat: 5, Lon: 55.5}},
+		"longitude":          {body: `{"location":{"lon":0}}`, expectedLocation: &GeoPoint{Lat: 25.4, Lon: 0}},
+		"empty location":     {body: `{"location":{}}`, expectedLocation: &GeoPoint{Lat: 25.4, Lon: 55.5}},
+		"the whole location": {body: `{"location":{"lat":5,"lon":6}}`, expectedLocation: &GeoPoint{Lat: 5, Lon: 6}},
+	}
+	for name, test := range locationPatches {
+		s.Run("should merge patch of "+name+" into stored location", func() {
+			// given
+			s.SetupTest()
+			s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Location: &GeoPoint{Lat: 25.4, Lon: 55.5}}
+			req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(test.body))
+			req.Header.Set("Content-Type", mergePatchContentType)
+
+			// when
+			recorder := s.serve(req)
+
+			// then
+			s.Require().Equal(http.StatusOK, recorder.Code)
+			s.Assert().Equal(test.expectedLocation, s.svc.ports["AEAJM"].Location)
+		})
+	}
+
+	s.Run("should reject removal of location member", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM", Location: &GeoPoint{Lat: 25.4, Lon: 55.5}}
+		req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(`{"location":{"lon":null}}`))
+		req.Header.Set("Content-Type", mergePatchContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+		s.Assert().Equal(&GeoPoint{Lat: 25.4, Lon: 55.5}, s.svc.ports["AEAJM"].Location)
+	})
+
+	s.Run("should reject partial location of port without location", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM"}
+		req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(`{"location":{"lat":5}}`))
+		req.Header.Set("Content-Type", mergePatchContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+		s.Assert().Nil(s.svc.ports["AEAJM"].Location)
+	})
+
+	s.Run("should reject patch of unsupported media type", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPatch, "/ports/AEAJM", bytes.NewBufferString(`name=Ajman`))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusUnsupportedMediaType, recorder.Code)
+		s.assertErrorBody(recorder, "unsupported_media_type")
+	})
+
+	s.Run("should delete port without body", func() {
+		// given
+		s.SetupTest()
+		s.svc.ports["AEAJM"] = &Port{ID: "AEAJM"}
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodDelete, "/ports/AEAJM", nil))
+
+		// then
+		s.Assert().Equal(http.StatusNoContent, recorder.Code)
+		s.Assert().Zero(recorder.Body.Len())
+		s.Assert().Empty(s.svc.ports)
+	})
+
+	s.Run("should return not found when deleting missing port", func() {
+		// given
+		s.SetupTest()
+
+		// when
+		recorder := s.serve(httptest.NewRequest(http.MethodDelete, "/ports/AEAJM", nil))
+
+		// then
+		s.Assert().Equal(http.StatusNotFound, recorder.Code)
+		s.assertErrorBody(recorder, "not_found")
+	})
+}
+
+func (s *serviceHandlerSuite) TestFindingPorts() {
+	tests := map[string]struct {
+		url          string
+		expectedCode int
+	}{
+		"search":                        {url: "/ports/search?q=ajman&limit=5", expectedCode: http.StatusOK},
+		"search without query":          {url: "/ports/search?q=+", expectedCode: http.StatusBadRequest},
+		"nearby":                        {url: "/ports/nearby?lat=25.4&lon=55.5", expectedCode: http.StatusOK},
+		"nearby without location":       {url: "/ports/nearby?k=1", expectedCode: http.StatusBadRequest},
+		"within":                        {url: "/ports/within?min_lat=1&min_lon=1&max_lat=2&max_lon=2", expectedCode: http.StatusOK},
+		"within with invalid longitude": {url: "/ports/within?min_lat=1&min_lon=x&max_lat=2&max_lon=2", expectedCode: http.StatusBadRequest},
+	}
+
+	for name, test := range tests {
+		s.Run(name, func() {
+			// given
+			s.SetupTest()
+
+			// when
+			recorder := s.serve(httptest.NewRequest(http.MethodGet, test.url, nil))
+
+			// then
+			s.Assert().Equal(test.expectedCode, recorder.Code)
+			s.Assert().True(json.Valid(recorder.Body.Bytes()), "body must be a single json value")
+		})
+	}
+}
+
+func (s *serviceHandlerSuite) TestRenderingUnavailableService() {
+	// given
+	s.SetupTest()
+	s.svc.err = &ServiceError{kind: ErrServiceUnavailable, Message: "connection refused"}
+
+	// when
+	recorder := s.serve(httptest.NewRequest(http.MethodGet, "/ports/AEAJM", nil))
+
+	// then
+	s.Assert().Equal(http.StatusServiceUnavailable, recorder.Code)
+	s.assertErrorBody(recorder, "unavailable")
+}
+
+func (s *serviceHandlerSuite) serve(req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.mux.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// assertErrorBody checks that the body is a single error response of the given code.
+func (s *serviceHandlerSuite) assertErrorBody(recorder *httptest.ResponseRecorder, code string) errorResp {
+	var resp errorResp
+	decoder := json.NewDecoder(recorder.Body)
+	s.Require().NoError(decoder.Decode(&resp))
+	s.Assert().Equal(code, resp.Code)
+	s.Assert().NotEmpty(resp.Error)
+	s.Assert().False(decoder.More(), "error must be the only value written to the body")
+	return resp
+}
+
+func (s *serviceHandlerSuite) multipartBody(content string) (io.Reader, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("ports", "ports.json")
+	s.Require().NoError(err)
+	_, err = part.Write([]byte(content))
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+// postSlowly sends parts of the body to the handler served with read and write timeouts shorter than
+// pauses between the parts.
+func postSlowly(t *testing.T, handler http.Handler, path, contentType string, parts ...string) *http.Response {
+	const timeout = 50 * time.Millisecond
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ReadTimeout = timeout
+	server.Config.WriteTimeout = timeout
+	server.Start()
+	t.Cleanup(server.Close)
+
+	body, bodyWriter := io.Pipe()
+	go func() {
+		for i, part := range parts {
+			if i > 0 {
+				time.Sleep(3 * timeout)
+			}
+			if _, err := bodyWriter.Write([]byte(part)); err != nil {
+				return
+			}
+		}
+		bodyWriter.Close()
+	}()
+	resp, err := http.Post(server.URL+path, contentType, body)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// fakePortsService keeps ports in a map. When err is set, every call fails with it.
+type fakePortsService struct {
+	ports map[string]*Port
+	err   error
+	// blockCreating makes CreatePorts wait, after all ports are read, until its context is canceled. Ports stored
+	// by non-atomic ingestion are returned along with the error of the context then
+	blockCreating bool
+	// calls counts calls of the service
+	calls int
+	// query, patchedFields and ingestOpts are the last ones received
+	query         PortsQuery
+	patchedFields []string
+	ingestOpts    IngestOptions
+}
+
+// CreatePorts rejects only entries which couldn't be decoded.
+func (f *fakePortsService) CreatePorts(ctx context.Context, opts IngestOptions, nextPort func() (*PortEntry, error),
+) (*IngestSummary, error) {
+	f.calls++
+	f.ingestOpts = opts
+	policy := opts.ErrorPolicy
+	if f.err != nil {
+		return nil, f.err
+	}
+	summary := &IngestSummary{Errors: []PortError{}}
+	var pending []*Port
+	for {
+		entry, err := nextPort()
+		if err == io.EOF && f.blockCreating {
+			<-ctx.Done()
+			if opts.atomic() {
+				return nil, ctx.Err()
+			}
+			return summary, ctx.Err()
+		}
+		if err == io.EOF {
+			break
+		}
+		var bodyErr *bodyError
+		if errors.As(err, &bodyErr) {
+			// pending ports of atomic ingestion are dropped
+			summary.Aborted = true
+			summary.Rejected++
+			summary.Errors = append(summary.Errors, PortError{Reason: bodyErr.Error(), Offset: bodyErr.offset})
+			return summary, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry.DecodeErr != nil {
+			portErr := PortError{PortID: entry.Port.ID, Reason: entry.DecodeErr.Error(), Offset: entry.Offset}
+			summary.Rejected++
+			summary.Errors = append(summary.Errors, portErr)
+			if opts.OnRejected != nil {
+				opts.OnRejected(portErr)
+			}
+			if policy == FailFast {
+				summary.Aborted = true
+				return summary, nil
+			}
+			continue
+		}
+		pending = append(pending, entry.Port)
+		if policy != AllOrNothing && !opts.Atomic && !opts.DryRun {
+			f.store(summary, pending...)
+			pending = nil
+		}
+	}
+	if summary.Rejected > 0 && policy == AllOrNothing {
+		summary.Aborted = true
+		return summary, nil
+	}
+	if opts.DryRun {
+		summary.DryRun = true
+		return summary, nil
+	}
+	f.store(summary, pending...)
+	return summary, nil
+}
+
+// DiffPorts reports only which ports are new and which are changed, without differences of their fields.
+func (f *fakePortsService) DiffPorts(_ context.Context, nextPort func() (*PortEntry, error)) (*PortsDiff, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	diff := &PortsDiff{Ports: []PortDiff{}, Errors: []PortError{}}
+	for {
+		entry, err := nextPort()
+		if err == io.EOF {
+			return diff, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		stored, ok := f.ports[entry.Port.ID]
+		switch {
+		case entry.DecodeErr != nil:
+			diff.Rejected++
+			diff.Errors = append(diff.Errors, PortError{
+				PortID: entry.Port.ID,
+				Reason: entry.DecodeErr.Error(),
+				Offset: entry.Offset,
+			})
+		case !ok:
+			diff.New++
+			diff.Ports = append(diff.Ports, PortDiff{PortID: entry.Port.ID, Status: PortNew, Offset: entry.Offset})
+		case !reflect.DeepEqual(stored, entry.Port):
+			diff.Changed++
+			diff.Ports = append(diff.Ports, PortDiff{PortID: entry.Port.ID, Status: PortChanged, Offset: entry.Offset})
+		default:
+			diff.Unchanged++
+		}
+	}
+}
+
+func (f *fakePortsService) store(summary *IngestSummary, ports ...*Port) {
+	for _, port := range ports {
+		if _, ok := f.ports[port.ID]; ok {
+			summary.Updated++
+			summary.UpdatedIDs = append(summary.UpdatedIDs, port.ID)
+		} else {
+			summary.Created++
+			summary.CreatedIDs = append(summary.CreatedIDs, port.ID)
+		}
+		f.ports[port.ID] = port
+	}
+}
+
+func (f *fakePortsService) UpdatePort(_ context.Context, port *Port) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.ports[port.ID]; !ok {
+		return &ServiceError{kind: ErrPortNotFound, Message: port.ID}
+	}
+	f.ports[port.ID] = port
+	return nil
+}
+
+func (f *fakePortsService) PatchPort(_ context.Context, patch *Port, fields []string) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	port, ok := f.ports[patch.ID]
+	if !ok {
+		return &ServiceError{kind: ErrPortNotFound, Message: patch.ID}
+	}
+	f.patchedFields = fields
+	for _, field := range fields {
+		switch field {
+		case "name":
+			port.Name = patch.Name
+		case "city":
+			port.City = patch.City
+		case "location":
+			port.Location = patch.Location
+		default:
+			return fmt.Errorf("patching %s isn't supported by fake service", field)
+		}
+	}
+	return nil
+}
+
+func (f *fakePortsService) DeletePort(_ context.Context, id string) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.ports[id]; !ok {
+		return &ServiceError{kind: ErrPortNotFound, Message: id}
+	}
+	delete(f.ports, id)
+	return nil
+}
+
+func (f *fakePortsService) FetchPort(_ context.Context, id string) (*Port, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	port, ok := f.ports[id]
+	if !ok {
+		return nil, &ServiceError{kind: ErrPortNotFound, Message: id}
+	}
+	return port, nil
+}
+
+func (f *fakePortsService) FetchPorts(_ context.Context, query PortsQuery) (*PortsPage, error) {
+	f.calls++
+	f.query = query
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &PortsPage{Ports: f.sortedPorts()}, nil
+}
+
+// StreamPorts fails, like Ports service stream does, only once the first port is fetched.
+func (f *fakePortsService) StreamPorts(_ context.Context, query PortsQuery) (func() (*Port, error), error) {
+	f.calls++
+	f.query = query
+	ports := f.sortedPorts()
+	return func() (*Port, error) {
+		if f.err != nil {
+			return nil, f.err
+		}
+		if len(ports) == 0 {
+			return nil, io.EOF
+		}
+		port := ports[0]
+		ports = ports[1:]
+		return port, nil
+	}, nil
+}
+
+func (f *fakePortsService) SearchPorts(context.Context, string, int32) (*SearchResults, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &SearchResults{Ports: f.sortedPorts()}, nil
+}
+
+func (f *fakePortsService) FindNearestPorts(context.Context, NearbyQuery) (*NearbyPorts, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &NearbyPorts{Ports: []NearbyPort{}}, nil
+}
+
+func (f *fakePortsService) FindPortsInBoundingBox(context.Context, BoundingBox, int32) (*PortsInBox, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &PortsInBox{Ports: f.sortedPorts()}, nil
+}
+
+func (f *fakePortsService) sortedPorts() []*Port {
+	ports := make([]*Port, 0, len(f.ports))
+	for _, port := range f.ports {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].ID < ports[j].ID })
+	return ports
+}
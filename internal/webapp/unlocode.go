@@ -0,0 +1,55 @@
+package webapp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/arturskrzydlo/ports/internal/unlocode"
+)
+
+// unlocodeFormat is the value of format query param of uploads of UN/LOCODE code list, which is csv
+// without a header row. It stands for media type of such uploads, since the code list has none of its own.
+const unlocodeFormat = "unlocode"
+
+// unlocodePorts returns a function yielding consecutive ports read from UN/LOCODE code list, see
+// unlocode.NewDecoder. It returns io.EOF when there are no more ports to read.
+func unlocodePorts(body io.Reader, subdivisions map[string]string) func() (*PortEntry, error) {
+	decoder := unlocode.NewDecoder(body, subdivisions)
+	return func() (*PortEntry, error) {
+		entry, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		var rowErr *unlocode.RowError
+		if errors.As(err, &rowErr) {
+			return nil, &bodyError{offset: rowErr.Offset, err: err}
+		}
+		if err != nil {
+			return nil, &bodyError{err: err}
+		}
+		portEntry := &PortEntry{Port: unlocodePort(entry.Port), Offset: entry.Offset}
+		if entry.Err != nil {
+			portEntry.DecodeErr = fmt.Errorf("failed to decode port: %w", entry.Err)
+		}
+		return portEntry, nil
+	}
+}
+
+func unlocodePort(port *unlocode.Port) *Port {
+	var location *GeoPoint
+	if port.Location != nil {
+		location = &GeoPoint{Lat: port.Location.Lat, Lon: port.Location.Lon}
+	}
+	return &Port{
+		ID:       port.ID,
+		Name:     port.Name,
+		City:     port.City,
+		Country:  port.Country,
+		Alias:    port.Alias,
+		Location: location,
+		Province: port.Province,
+		Unlocs:   port.Unlocs,
+		Code:     port.Code,
+	}
+}
@@ -0,0 +1,97 @@
+package webapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (s *serviceHandlerSuite) TestImportingUNLOCODECodeList() {
+	const codeList = `,"AE",,".UNITED ARAB EMIRATES",,,,,,,,` + "\n" +
+		`,"AE","AJM","Ajman","Ajman","AJ","1-------","AI","0601","","2524N 05530E",""` + "\n" +
+		`,"AE","DXB","Dubai","Dubai","DU","-2345---","AI","0601","","",""` + "\n" +
+		`,"AE","BAD","Bad","Bad","","1-------","AI","0601","","2524N 05590E",""` + "\n"
+
+	s.Run("should store ports of code list", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports?format=unlocode", bytes.NewBufferString(codeList))
+		req.Header.Set("Content-Type", csvContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusCreated, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().Equal([]PortError{{
+			PortID: "AEBAD",
+			Reason: `failed to decode port: invalid coordinates "2524N 05590E": minutes must be less than 60, got 90`,
+			Offset: 181,
+		}}, summary.Errors)
+		s.Assert().Equal(map[string]*Port{"AEAJM": {
+			ID:       "AEAJM",
+			Name:     "Ajman",
+			City:     "Ajman",
+			Country:  "United Arab Emirates",
+			Location: &GeoPoint{Lat: 25.4, Lon: 55.5},
+			Province: "AE-AJ",
+			Unlocs:   []string{"AEAJM"},
+			Code:     "AEAJM",
+		}}, s.svc.ports)
+	})
+
+	s.Run("should reject code list which isn't csv", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports?format=unlocode", bytes.NewBufferString(codeList))
+		req.Header.Set("Content-Type", ndjsonContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusUnsupportedMediaType, recorder.Code)
+		s.assertErrorBody(recorder, "unsupported_media_type")
+		s.Assert().Zero(s.svc.calls)
+	})
+
+	s.Run("should reject unknown format", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports?format=xml", bytes.NewBufferString(codeList))
+		req.Header.Set("Content-Type", csvContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Assert().Equal(http.StatusBadRequest, recorder.Code)
+		s.assertErrorBody(recorder, "invalid_argument")
+		s.Assert().Zero(s.svc.calls)
+	})
+
+	s.Run("should abort ingestion of csv with header row as code list", func() {
+		// given
+		s.SetupTest()
+		req := httptest.NewRequest(http.MethodPost, "/ports?format=unlocode",
+			bytes.NewBufferString("id,name\nAEAJM,Ajman\n"))
+		req.Header.Set("Content-Type", csvContentType)
+
+		// when
+		recorder := s.serve(req)
+
+		// then
+		s.Require().Equal(http.StatusUnprocessableEntity, recorder.Code)
+		var summary IngestSummary
+		s.Require().NoError(json.NewDecoder(recorder.Body).Decode(&summary))
+		s.Assert().True(summary.Aborted)
+		s.Require().Len(summary.Errors, 1)
+		s.Assert().Equal(PortError{
+			Reason: "row at offset 0 isn't UN/LOCODE entry, it has 2 columns instead of at least 11",
+		}, summary.Errors[0])
+		s.Assert().Empty(s.svc.ports)
+	})
+}